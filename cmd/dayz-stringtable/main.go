@@ -74,6 +74,60 @@ func main() {
 			"Clean msgstr equal to msgid in PO files",
 			"Clear msgstr when it duplicates msgid across PO files",
 		},
+		{
+			&commands.PrefillCmd{},
+			"prefill",
+			"Prefill untranslated entries from identical originals",
+			"Copy msgstr of translated entries with the same msgid, from the same PO file or --from directories, marked fuzzy for review",
+		},
+		{
+			&commands.CheckCmd{},
+			"check",
+			"Run QA checks of translations",
+			"Compare every msgstr with its msgid: placeholders, markup, line breaks, whitespace and untranslated copies",
+		},
+		{
+			&commands.FmtCmd{},
+			"fmt",
+			"Format CSV in canonical style",
+			"Rewrite CSV with every field quoted, trailing comma and fixed line endings, or check formatting with --check",
+		},
+		{
+			&commands.DiffCmd{},
+			"diff",
+			"Compare stringtables and PO files across revisions",
+			"Show added, removed and changed strings and translations between two CSV/PO sets or a git revision and the working tree",
+		},
+		{
+			&commands.MergeDriverCmd{},
+			"merge-driver",
+			"Git merge driver for PO files",
+			"Merge PO files entry by entry (git %O %A %B), or register the driver with 'merge-driver install'",
+		},
+		{
+			&commands.RenameCmd{},
+			"rename",
+			"Rename a key in CSV, PO, POT and sources",
+			"Rename a key in CSV, every PO file, the POT template and optionally in source references",
+		},
+		{
+			&commands.RemoveCmd{},
+			"rm",
+			"Remove a key from CSV, PO and POT",
+			"Remove a key from CSV, every PO file and the POT template, reporting remaining source references",
+		},
+		{
+			&commands.PseudoCmd{},
+			"pseudo",
+			"Generate pseudo-localized PO file or CSV column",
+			"Write originals with accented letters, expansion, brackets or mirrored/RTL text, keeping placeholders and markup, to find hardcoded strings and clipped widgets",
+		},
+		{
+			&commands.ScanCmd{},
+			"scan",
+			"Scan mod sources for key usage",
+			"Find #KEY, $KEY and Localize() key references in sources and report missing and unused keys",
+		},
 		{
 			commands.NewTranslateCmd(),
 			"translate",
@@ -84,6 +138,11 @@ func main() {
 		mustAdd(parser, c.name, c.desc, c.longDesc, c.cmd)
 	}
 
+	// merge-driver runs with positional args, install is optional
+	if c := parser.Find("merge-driver"); c != nil {
+		c.SubcommandsOptional = true
+	}
+
 	_, err := parser.Parse()
 	if err != nil {
 		if opts.Version {
@@ -6,6 +6,7 @@ require (
 	github.com/cespare/xxhash/v2 v2.3.0
 	github.com/jessevdk/go-flags v1.6.1
 	github.com/joho/godotenv v1.5.1
+	golang.org/x/text v0.34.0
 )
 
 require golang.org/x/sys v0.40.0 // indirect
@@ -0,0 +1,434 @@
+package commands
+
+//lint:file-ignore SA5008 go-flags requires duplicate choice tags on struct fields
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/woozymasta/dayz-stringtable/internal/csvutil"
+	"github.com/woozymasta/dayz-stringtable/internal/font"
+	"github.com/woozymasta/dayz-stringtable/internal/junit"
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+	"github.com/woozymasta/dayz-stringtable/internal/qa"
+	"github.com/woozymasta/dayz-stringtable/internal/sarif"
+	"github.com/woozymasta/dayz-stringtable/internal/vars"
+)
+
+// CheckCmd runs QA checks comparing every msgstr with its msgid.
+//
+// Usage: dayz-stringtable check --podir l18n [--lang russian] [--severity identical:off] [--max-length STR_HUD_:12] [--max-ratio 1.5] [--glossary glossary.csv] [--spell --spell-ignore words.txt] [--font font.ttf] [--fix] [--format json] [--strict]
+type CheckCmd struct {
+	LengthOptions
+	SpellOptions
+
+	PoDir    string            `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
+	Format   string            `short:"f" long:"format" description:"Output format" default:"text" choice:"text" choice:"json" choice:"sarif" choice:"junit"`
+	Langs    []string          `short:"l" long:"lang" description:"Check only these languages (repeatable or comma separated)"`
+	Severity map[string]string `short:"S" long:"severity" description:"Override check severity as check:error|warning|off (repeatable)"`
+	Glossary string            `short:"g" long:"glossary" description:"Glossary CSV with terms and their required translations"`
+	Fonts    []string          `long:"font" description:"TTF/OTF font the texts are rendered with, characters missing from all fonts are reported (repeatable)"`
+	Strict   bool              `long:"strict" description:"Exit with error on warnings too"`
+	Fix      bool              `long:"fix" description:"Normalize translations to NFC and remove invisible characters before checking"`
+	List     bool              `long:"list" description:"List available checks and exit"`
+}
+
+// LengthOptions are the length limit options shared by check and stats.
+type LengthOptions struct {
+	MaxLength      map[string]string `long:"max-length" description:"Max translation length of keys with a prefix, as prefix:length (repeatable); max-length flags of entries take precedence"`
+	MaxRatio       float64           `long:"max-ratio" description:"Max translation length relative to the original, e.g. 1.5 (0 disables)"`
+	RatioMinLength int               `long:"ratio-min-length" description:"Skip the ratio check for originals shorter than this" default:"10"`
+}
+
+// limits converts the options into length limits.
+func (o *LengthOptions) limits() (*qa.LengthLimits, error) {
+	limits := &qa.LengthLimits{
+		PrefixMaxLength: make(map[string]int, len(o.MaxLength)),
+		MaxRatio:        o.MaxRatio,
+		RatioMinLength:  o.RatioMinLength,
+	}
+	for prefix, value := range o.MaxLength {
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid max length '%s' for prefix '%s'", value, prefix)
+		}
+		limits.PrefixMaxLength[prefix] = n
+	}
+	if o.MaxRatio < 0 {
+		return nil, fmt.Errorf("max ratio must not be negative")
+	}
+	return limits, nil
+}
+
+// CheckReport holds findings of a check run.
+type CheckReport struct {
+	// MissingGlyphs maps languages to characters missing from fonts and
+	// the keys where they appear.
+	MissingGlyphs map[string]map[string][]string `json:"missing_glyphs,omitempty"`
+	Findings      []qa.Finding                   `json:"findings"`
+	Errors        int                            `json:"errors"`
+	Warnings      int                            `json:"warnings"`
+}
+
+// Execute checks PO files and prints findings.
+func (cmd *CheckCmd) Execute(_ []string) error {
+	if cmd.List {
+		checker, err := cmd.newChecker(nil, nil)
+		if err != nil {
+			return err
+		}
+		return printChecks(checker)
+	}
+
+	poFiles, err := listPOFiles(cmd.PoDir)
+	if err != nil {
+		return err
+	}
+	langs, err := selectLangs(cmd.Langs, nil, poFiles)
+	if err != nil {
+		return err
+	}
+	if len(langs) == 0 {
+		return fmt.Errorf("no PO files found in directory '%s'", cmd.PoDir)
+	}
+
+	speller, err := cmd.speller(langs)
+	if err != nil {
+		return err
+	}
+	fonts, err := loadFonts(cmd.Fonts)
+	if err != nil {
+		return err
+	}
+	checker, err := cmd.newChecker(speller, fonts)
+	if err != nil {
+		return err
+	}
+
+	report := &CheckReport{Findings: []qa.Finding{}}
+	for n, lang := range langs {
+		path := poFiles[lang]
+		po, err := poutil.ParseFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if cmd.Fix {
+			if err := fixPOFile(path, po); err != nil {
+				return err
+			}
+		}
+
+		findings := checker.CheckFile(lang, po)
+		// Original texts are the same in every PO file, check them once
+		if n == 0 {
+			findings = append(findings, checker.CheckOriginals(po)...)
+		}
+		if len(findings) == 0 {
+			continue
+		}
+		lines := indexMsgctxtLines(path)
+		for i := range findings {
+			findings[i].File = path
+			findings[i].Line = lines[findings[i].Key]
+		}
+		report.Findings = append(report.Findings, findings...)
+		report.addMissingGlyphs(fonts, po, findings)
+	}
+	report.Errors, report.Warnings = qa.Count(report.Findings)
+
+	switch cmd.Format {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "sarif":
+		if err := checkSARIF(checker, report).Write(os.Stdout); err != nil {
+			return err
+		}
+	case "junit":
+		if err := checkJUnit(checker, report, langs, cmd.Strict, cmd.notRun(speller, fonts)).Write(os.Stdout); err != nil {
+			return err
+		}
+	default:
+		printCheckReport(report, len(langs))
+	}
+
+	if report.Errors > 0 || (cmd.Strict && report.Warnings > 0) {
+		return fmt.Errorf("found %d errors and %d warnings", report.Errors, report.Warnings)
+	}
+	return nil
+}
+
+// newChecker builds the checker with all checks and severity overrides.
+// The spelling and glyph checks do nothing without a speller and fonts.
+func (cmd *CheckCmd) newChecker(speller *qa.Speller, fonts []*font.Font) (*qa.Checker, error) {
+	limits, err := cmd.limits()
+	if err != nil {
+		return nil, err
+	}
+
+	var glossary *qa.Glossary
+	if cmd.Glossary != "" {
+		if glossary, err = qa.LoadGlossary(cmd.Glossary); err != nil {
+			return nil, err
+		}
+	}
+
+	checks := qa.FormatChecks()
+	checks = append(checks, qa.CharsetChecks()...)
+	checks = append(checks, qa.LengthChecks(limits)...)
+	checks = append(checks, qa.GlossaryCheck(glossary), qa.SpellCheck(speller), qa.GlyphCheck(fonts))
+	checker := qa.NewChecker(checks...)
+	for name, value := range cmd.Severity {
+		sev, err := qa.ParseSeverity(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := checker.SetSeverity(strings.TrimSpace(name), sev); err != nil {
+			return nil, err
+		}
+	}
+	return checker, nil
+}
+
+// loadFonts loads the fonts of the --font option.
+func loadFonts(paths []string) ([]*font.Font, error) {
+	fonts := make([]*font.Font, 0, len(paths))
+	for _, path := range paths {
+		f, err := font.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		fonts = append(fonts, f)
+	}
+	return fonts, nil
+}
+
+// addMissingGlyphs collects characters of glyph findings by language and
+// character with the keys where they appear.
+func (r *CheckReport) addMissingGlyphs(fonts []*font.Font, po *poutil.File, findings []qa.Finding) {
+	var entries map[string]*poutil.Entry
+	for _, f := range findings {
+		if f.Check != "glyphs" {
+			continue
+		}
+		if entries == nil {
+			entries = make(map[string]*poutil.Entry, len(po.Entries))
+			for _, entry := range po.Entries {
+				entries[entry.Context] = entry
+			}
+		}
+		entry := entries[f.Key]
+		if entry == nil {
+			continue
+		}
+		text := entry.MsgStr
+		if f.Language == qa.OriginalLanguage {
+			text = entry.MsgID
+		}
+
+		if r.MissingGlyphs == nil {
+			r.MissingGlyphs = make(map[string]map[string][]string)
+		}
+		chars := r.MissingGlyphs[f.Language]
+		if chars == nil {
+			chars = make(map[string][]string)
+			r.MissingGlyphs[f.Language] = chars
+		}
+		for _, c := range qa.MissingGlyphs(fonts, text) {
+			chars[string(c)] = append(chars[string(c)], f.Key)
+		}
+	}
+}
+
+// fixPOFile applies safe normalizations (qa.Fix) to translations of a PO
+// file and writes it back if any changed.
+func fixPOFile(path string, po *poutil.File) error {
+	fixed := 0
+	for _, entry := range po.Entries {
+		if text := qa.Fix(entry.MsgStr); text != entry.MsgStr {
+			entry.MsgStr = text
+			fixed++
+		}
+	}
+	if fixed == 0 {
+		return nil
+	}
+
+	po.UpdateBuildHeaders("")
+	data, err := po.MarshalText()
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	if err := csvutil.WriteFile(path, data, true); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Fprintf(os.Stderr, "fixed %d entries in %s\n", fixed, path)
+	return nil
+}
+
+// printChecks lists checks with their effective severity.
+func printChecks(checker *qa.Checker) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, check := range checker.Checks {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", check.Name, checker.Severity(check), check.Description); err != nil {
+			return fmt.Errorf("failed to write checks: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush checks: %w", err)
+	}
+	return nil
+}
+
+// checkSARIF converts a check report to a SARIF log with a rule per check.
+func checkSARIF(checker *qa.Checker, report *CheckReport) *sarif.Log {
+	log := sarif.New("dayz-stringtable", vars.Version, vars.URL)
+	for _, check := range checker.Checks {
+		log.AddRule(check.Name, check.Description, sarifLevel(checker.Severity(check)))
+	}
+	for _, f := range report.Findings {
+		log.AddResult(f.Check, sarifLevel(f.Severity), fmt.Sprintf("%s [%s]: %s", f.Key, f.Language, f.Message), f.File, f.Line)
+	}
+	return log
+}
+
+// checkJUnit converts a check report to a JUnit report with a suite per
+// language. Every finding is a test case, failed for errors (and for
+// warnings in strict mode) or skipped for warnings, and checks without
+// findings are passed test cases. Checks that notRun reports as not run
+// for a language, if notRun is set, are skipped test cases instead.
+func checkJUnit(checker *qa.Checker, report *CheckReport, langs []string, strict bool, notRun func(check, lang string) string) *junit.Report {
+	byLang := make(map[string][]qa.Finding)
+	for _, f := range report.Findings {
+		byLang[f.Language] = append(byLang[f.Language], f)
+	}
+
+	result := junit.New("dayz-stringtable check")
+	addSuite := func(lang string, original bool) {
+		suite := result.Suite(lang)
+		failed := make(map[string]bool)
+		for _, f := range byLang[lang] {
+			failed[f.Check] = true
+			c := suite.Add(f.Check+": "+f.Key).At(f.File, f.Line)
+			if f.Severity == qa.SeverityError || strict {
+				c.Fail(f.Check, f.Message, "")
+			} else {
+				c.Skip(f.Message)
+			}
+		}
+		for _, check := range checker.Checks {
+			if failed[check.Name] || checker.Severity(check) == qa.SeverityOff || (original && check.RunOriginal == nil) {
+				continue
+			}
+			c := suite.Add(check.Name)
+			if notRun == nil {
+				continue
+			}
+			if reason := notRun(check.Name, lang); reason != "" {
+				c.Skip(reason)
+			}
+		}
+	}
+
+	for _, lang := range langs {
+		addSuite(lang, false)
+	}
+	addSuite(qa.OriginalLanguage, true)
+	return result
+}
+
+// notRun returns a function reporting why a check did not run for a
+// language: the spelling, glyph and glossary checks need their options
+// and spelling a dictionary of the language.
+func (cmd *CheckCmd) notRun(speller *qa.Speller, fonts []*font.Font) func(check, lang string) string {
+	return func(check, lang string) string {
+		switch {
+		case check == "spelling" && speller == nil:
+			return "not run: --spell not given"
+		case check == "spelling" && speller.Dictionaries[lang] == nil:
+			return "not run: no dictionary for " + lang
+		case check == "glyphs" && len(fonts) == 0:
+			return "not run: --font not given"
+		case check == "glossary" && cmd.Glossary == "":
+			return "not run: --glossary not given"
+		}
+		return ""
+	}
+}
+
+// sarifLevel converts a severity to a SARIF level.
+func sarifLevel(sev qa.Severity) string {
+	switch sev {
+	case qa.SeverityError:
+		return sarif.LevelError
+	case qa.SeverityWarning:
+		return sarif.LevelWarning
+	}
+	return sarif.LevelNone
+}
+
+// printCheckReport prints findings in file:line format followed by
+// characters missing from fonts and a summary.
+func printCheckReport(report *CheckReport, langs int) {
+	for _, f := range report.Findings {
+		fmt.Printf("%s:%d: %s [%s] %s: %s\n", f.File, f.Line, f.Severity, f.Check, f.Key, f.Message)
+	}
+
+	for _, lang := range sortedKeys(report.MissingGlyphs) {
+		chars := report.MissingGlyphs[lang]
+		fmt.Printf("%s: %d characters missing from fonts\n", lang, len(chars))
+		for _, c := range sortedKeys(chars) {
+			fmt.Printf("  %q U+%04X: %s\n", c, []rune(c)[0], strings.Join(chars[c], ", "))
+		}
+	}
+	fmt.Printf("%d languages checked: %d errors, %d warnings\n", langs, report.Errors, report.Warnings)
+}
+
+// indexMsgctxtLines maps msgctxt values of a PO file to their line numbers.
+// Returns an empty map if the file can't be read.
+func indexMsgctxtLines(path string) map[string]int {
+	lines := make(map[string]int)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return lines
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "msgctxt ") {
+			continue
+		}
+		key, err := strconv.Unquote(strings.TrimSpace(strings.TrimPrefix(line, "msgctxt ")))
+		if err != nil {
+			continue
+		}
+		if _, ok := lines[key]; !ok {
+			lines[key] = lineNum
+		}
+	}
+	return lines
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
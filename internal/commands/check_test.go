@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+	"github.com/woozymasta/dayz-stringtable/internal/qa"
+)
+
+// writeCheckFixture writes a russian.po with the given entries and returns the PO directory.
+func writeCheckFixture(t *testing.T, entries ...*poutil.Entry) string {
+	t.Helper()
+	poDir := t.TempDir()
+
+	po := poutil.NewFile()
+	po.Language = "russian"
+	po.SetHeader("Language", "russian")
+	po.Entries = append(po.Entries, entries...)
+	data, err := po.MarshalText()
+	if err != nil {
+		t.Fatalf("failed to marshal PO: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(poDir, "russian.po"), data, 0o644); err != nil {
+		t.Fatalf("failed to write PO: %v", err)
+	}
+	return poDir
+}
+
+func TestCheckCmd(t *testing.T) {
+	poDir := writeCheckFixture(t,
+		&poutil.Entry{Context: "STR_Ok", MsgID: "Yes", MsgStr: "Да"},
+		&poutil.Entry{Context: "STR_Ammo", MsgID: "Ammo: %1 / %2", MsgStr: "Патроны: %1"},
+	)
+
+	cmd := &CheckCmd{PoDir: poDir, Format: "json"}
+	if err := cmd.Execute(nil); err == nil {
+		t.Fatal("expected error for broken placeholder")
+	}
+
+	cmd.Severity = map[string]string{"placeholders": "warning"}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("expected warnings only, got: %v", err)
+	}
+
+	cmd.Strict = true
+	if err := cmd.Execute(nil); err == nil {
+		t.Fatal("expected error for warnings in strict mode")
+	}
+}
+
+func TestCheckJUnit(t *testing.T) {
+	report := &CheckReport{Findings: []qa.Finding{
+		{Language: "russian", Key: "STR_Ammo", Check: "placeholders", Severity: qa.SeverityError, Message: "missing %2"},
+		{Language: "russian", Key: "STR_Ok", Check: "whitespace", Severity: qa.SeverityWarning, Message: "trailing space"},
+	}}
+	checker := qa.NewChecker(qa.FormatChecks()...)
+
+	suites := checkJUnit(checker, report, []string{"russian"}, false, nil).Suites
+	if len(suites) != 2 || suites[0].Name != "russian" || suites[1].Name != qa.OriginalLanguage {
+		t.Fatalf("unexpected suites %+v", suites)
+	}
+	cases := suites[0].Cases
+	if cases[0].Name != "placeholders: STR_Ammo" || cases[0].Failure == nil {
+		t.Errorf("error finding not failed: %+v", cases[0])
+	}
+	if cases[1].Skipped == nil {
+		t.Errorf("warning finding not skipped: %+v", cases[1])
+	}
+	if len(cases) != len(checker.Checks) {
+		t.Errorf("got %d cases, want a case per check: %+v", len(cases), cases)
+	}
+
+	suites = checkJUnit(checker, report, []string{"russian"}, true, nil).Suites
+	if suites[0].Cases[1].Failure == nil {
+		t.Errorf("warning finding not failed in strict mode: %+v", suites[0].Cases[1])
+	}
+}
+
+func TestCheckJUnit_NotRun(t *testing.T) {
+	checker := qa.NewChecker(qa.SpellCheck(nil), qa.GlyphCheck(nil))
+	cmd := &CheckCmd{}
+
+	suites := checkJUnit(checker, &CheckReport{}, []string{"russian"}, false, cmd.notRun(nil, nil)).Suites
+	for _, suite := range suites {
+		if len(suite.Cases) != 2 {
+			t.Fatalf("suite %s: got %d cases, want 2: %+v", suite.Name, len(suite.Cases), suite.Cases)
+		}
+		for _, c := range suite.Cases {
+			if c.Skipped == nil {
+				t.Errorf("suite %s: check %s without options not skipped", suite.Name, c.Name)
+			}
+		}
+	}
+}
+
+func TestIndexMsgctxtLines(t *testing.T) {
+	poDir := writeCheckFixture(t,
+		&poutil.Entry{Context: "STR_A", MsgID: "A"},
+		&poutil.Entry{Context: "STR_B", MsgID: "B", Comments: []string{"# note"}},
+	)
+
+	lines := indexMsgctxtLines(filepath.Join(poDir, "russian.po"))
+	if lines["STR_A"] == 0 || lines["STR_B"] != lines["STR_A"]+5 {
+		t.Errorf("unexpected lines %v", lines)
+	}
+}
+
+func TestCheckCmdSpell(t *testing.T) {
+	poDir := writeCheckFixture(t,
+		&poutil.Entry{Context: "STR_Load", MsgID: "Load", MsgStr: "Зарядить"},
+		&poutil.Entry{Context: "STR_Mosin", MsgID: "Mosin", MsgStr: "Мосинка"},
+	)
+	dictDir := t.TempDir()
+	for name, content := range map[string]string{
+		"ru_RU.aff": "SET UTF-8\n",
+		"ru_RU.dic": "1\nзарядить\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dictDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write dictionary: %v", err)
+		}
+	}
+
+	cmd := &CheckCmd{PoDir: poDir, Format: "json", Strict: true}
+	cmd.Spell = true
+	cmd.SpellDir = dictDir
+	if err := cmd.Execute(nil); err == nil {
+		t.Fatal("expected error for unknown word in strict mode")
+	}
+
+	ignore := filepath.Join(dictDir, "ignore.txt")
+	if err := os.WriteFile(ignore, []byte("# jargon\nМосинка\n"), 0o644); err != nil {
+		t.Fatalf("failed to write ignore list: %v", err)
+	}
+	cmd.SpellIgnore = ignore
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("expected no findings with ignore list, got: %v", err)
+	}
+}
+
+func TestCheckCmdFix(t *testing.T) {
+	poDir := writeCheckFixture(t,
+		&poutil.Entry{Context: "STR_Drop", MsgID: "Drop all", MsgStr: "Выбросить\u00a0всё\u200b"},
+	)
+
+	cmd := &CheckCmd{PoDir: poDir, Format: "json"}
+	if err := cmd.Execute(nil); err == nil {
+		t.Fatal("expected error for invisible characters")
+	}
+
+	cmd.Fix = true
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("expected no errors after fix, got: %v", err)
+	}
+	po, err := poutil.ParseFile(filepath.Join(poDir, "russian.po"))
+	if err != nil {
+		t.Fatalf("failed to parse PO: %v", err)
+	}
+	if got := po.GetC("STR_Drop", "Drop all"); got != "Выбросить всё" {
+		t.Errorf("fixed msgstr = %q", got)
+	}
+}
@@ -14,13 +14,14 @@ import (
 // CleanCmd clears msgstr entries that are identical to msgid in PO files.
 // This is useful for cleaning up machine-translated or copied entries.
 //
-// Usage: dayz-stringtable clean --podir l18n [--clear-only] [--input csv] [--remove-unused]
+// Usage: dayz-stringtable clean --podir l18n [--clear-only] [--input csv] [--remove-unused] [--rules notranslate.rules]
 type CleanCmd struct {
 	PoDir        string   `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
 	Input        string   `short:"i" long:"input" description:"CSV input file (required for --remove-unused)"`
 	Langs        []string `short:"l" long:"lang" description:"Filter by languages (comma-separated or repeatable)"`
 	ClearOnly    bool     `short:"c" long:"clear-only" description:"Don't add notranslate comment, just clear msgstr"`
 	RemoveUnused bool     `short:"u" long:"remove-unused" description:"Remove entries not present in CSV file"`
+	Rules        string   `long:"rules" description:"Do-not-translate rule file, matching entries are marked notranslate"`
 }
 
 // Execute processes all PO files in the directory and clears msgstr entries that match msgid.
@@ -54,6 +55,11 @@ func (cmd *CleanCmd) Execute(_ []string) error {
 		}
 	}
 
+	rules, err := loadNoTranslateRules(cmd.Rules)
+	if err != nil {
+		return err
+	}
+
 	files, err := filepath.Glob(filepath.Join(cmd.PoDir, "*.po"))
 	if err != nil {
 		return err
@@ -64,7 +70,8 @@ func (cmd *CleanCmd) Execute(_ []string) error {
 
 	perLangCleaned := make(map[string]int)
 	perLangRemoved := make(map[string]int)
-	var totalCleaned, totalRemoved int
+	perLangMarked := make(map[string]int)
+	var totalCleaned, totalRemoved, totalMarked int
 
 	for _, path := range files {
 		lang := ExtractLanguageName(path)
@@ -73,45 +80,53 @@ func (cmd *CleanCmd) Execute(_ []string) error {
 			continue
 		}
 
-		cleaned, removed, err := cmd.cleanPOFile(path, validKeys)
+		cleaned, removed, marked, err := cmd.cleanPOFile(path, validKeys, rules)
 		if err != nil {
 			return fmt.Errorf("clean %s: %w", path, err)
 		}
 		totalCleaned += cleaned
 		totalRemoved += removed
+		totalMarked += marked
 		perLangCleaned[lang] += cleaned
 		perLangRemoved[lang] += removed
+		perLangMarked[lang] += marked
 	}
 
-	// summary per language (only if something was cleaned or removed)
-	if totalCleaned > 0 || totalRemoved > 0 {
-		for _, lang := range orderLangs(perLangCleaned, perLangRemoved) {
-			cleaned := perLangCleaned[lang]
-			removed := perLangRemoved[lang]
-			if cleaned > 0 && removed > 0 {
-				fmt.Printf("lang %s: %d cleaned, %d removed\n", lang, cleaned, removed)
-			} else if cleaned > 0 {
-				fmt.Printf("lang %s: %d cleaned\n", lang, cleaned)
-			} else if removed > 0 {
-				fmt.Printf("lang %s: %d removed\n", lang, removed)
+	// summary per language (only if something was cleaned, removed or marked)
+	if totalCleaned > 0 || totalRemoved > 0 || totalMarked > 0 {
+		for _, lang := range orderLangs(perLangCleaned, perLangRemoved, perLangMarked) {
+			var parts []string
+			if n := perLangCleaned[lang]; n > 0 {
+				parts = append(parts, fmt.Sprintf("%d cleaned", n))
+			}
+			if n := perLangRemoved[lang]; n > 0 {
+				parts = append(parts, fmt.Sprintf("%d removed", n))
+			}
+			if n := perLangMarked[lang]; n > 0 {
+				parts = append(parts, fmt.Sprintf("%d marked notranslate", n))
+			}
+			if len(parts) > 0 {
+				fmt.Printf("lang %s: %s\n", lang, strings.Join(parts, ", "))
 			}
 		}
 	}
+	rules.printReport(os.Stdout)
 	return nil
 }
 
-// cleanPOFile processes a single PO file, clearing duplicate msgstr and optionally removing unused entries.
-// Returns the number of cleaned and removed entries.
-func (cmd *CleanCmd) cleanPOFile(path string, validKeys map[string]bool) (cleaned int, removed int, err error) {
+// cleanPOFile processes a single PO file, clearing duplicate msgstr, optionally removing unused entries
+// and marking entries matched by do-not-translate rules.
+// Returns the number of cleaned, removed and marked entries.
+func (cmd *CleanCmd) cleanPOFile(path string, validKeys map[string]bool, rules *noTranslateRules) (cleaned, removed, marked int, err error) {
 	file, err := os.Open(path)
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, 0, err
 	}
 	defer func() { _ = file.Close() }()
 
 	po, err := poutil.ParseReader(file)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to parse PO file: %w", err)
+		return 0, 0, 0, fmt.Errorf("failed to parse PO file: %w", err)
 	}
 
 	// First pass: clear msgstr entries that duplicate msgid
@@ -151,8 +166,15 @@ func (cmd *CleanCmd) cleanPOFile(path string, validKeys map[string]bool) (cleane
 		po.Entries = filteredEntries
 	}
 
-	if cleaned == 0 && removed == 0 {
-		return 0, 0, nil
+	// Third pass: mark entries matched by rules, cleared msgstr stays empty
+	for _, entry := range po.Entries {
+		if rules.apply(entry) {
+			marked++
+		}
+	}
+
+	if cleaned == 0 && removed == 0 && marked == 0 {
+		return 0, 0, 0, nil
 	}
 
 	// Update build headers after modifications
@@ -161,24 +183,23 @@ func (cmd *CleanCmd) cleanPOFile(path string, validKeys map[string]bool) (cleane
 	// Write back
 	data, err := po.MarshalText()
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to marshal PO file: %w", err)
+		return 0, 0, 0, fmt.Errorf("failed to marshal PO file: %w", err)
 	}
 
 	if err := os.WriteFile(path, data, 0o600); err != nil {
-		return 0, 0, err
+		return 0, 0, 0, err
 	}
 
-	return cleaned, removed, nil
+	return cleaned, removed, marked, nil
 }
 
 // orderLangs returns languages in sorted order from the given maps.
-func orderLangs(cleaned, removed map[string]int) []string {
+func orderLangs(counts ...map[string]int) []string {
 	langSet := make(map[string]bool)
-	for l := range cleaned {
-		langSet[l] = true
-	}
-	for l := range removed {
-		langSet[l] = true
+	for _, m := range counts {
+		for l := range m {
+			langSet[l] = true
+		}
 	}
 	var langs []string
 	for l := range langSet {
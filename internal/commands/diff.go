@@ -0,0 +1,463 @@
+package commands
+
+//lint:file-ignore SA5008 go-flags requires duplicate choice tags on struct fields
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/woozymasta/dayz-stringtable/internal/csvutil"
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// DiffCmd compares stringtables and PO directories between two states,
+// either two sets of files or a git revision against the working tree.
+// Only entries are compared, so header churn (PO-Revision-Date,
+// X-Content-Hash, etc.) never shows up.
+//
+// Usage: dayz-stringtable diff --input stringtable.csv --podir l18n (--ref v1.0.0 | --old-input old.csv --old-podir old_l18n) [--format markdown]
+type DiffCmd struct {
+	Input    string   `short:"i" long:"input" description:"CSV file (new side)" default:"stringtable.csv"`
+	PoDir    string   `short:"d" long:"podir" description:"Directory for PO files (new side)" default:"l18n"`
+	Ref      string   `short:"r" long:"ref" description:"Git revision to read the old side from (same paths as the new side)"`
+	OldInput string   `long:"old-input" description:"CSV file (old side), overrides --ref for CSV"`
+	OldPoDir string   `long:"old-podir" description:"Directory for PO files (old side), overrides --ref for PO"`
+	Format   string   `short:"f" long:"format" description:"Output format" default:"text" choice:"text" choice:"json" choice:"markdown"`
+	Langs    []string `short:"l" long:"lang" description:"Filter by languages (comma-separated or repeatable)"`
+}
+
+// StringChange describes an added, removed or changed original string.
+type StringChange struct {
+	Key string `json:"key"`
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// TranslationChange describes a changed msgstr in a language.
+type TranslationChange struct {
+	Key      string `json:"key"`
+	Original string `json:"original"`
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new,omitempty"`
+}
+
+// LangDiff holds translation changes of a single language.
+type LangDiff struct {
+	Translated []TranslationChange `json:"translated"` // Empty before, translated now
+	Changed    []TranslationChange `json:"changed"`    // Translated before and now, different text
+	Cleared    []TranslationChange `json:"cleared"`    // Translated before, empty or gone now
+}
+
+// DiffReport holds all differences between the old and new side.
+type DiffReport struct {
+	Strings   *StringsDiff         `json:"strings,omitempty"`
+	Languages map[string]*LangDiff `json:"languages,omitempty"`
+}
+
+// StringsDiff holds differences of original strings in the CSV.
+type StringsDiff struct {
+	Added   []StringChange `json:"added"`
+	Removed []StringChange `json:"removed"`
+	Changed []StringChange `json:"changed"`
+}
+
+// Execute loads both sides, compares them and prints the report.
+func (cmd *DiffCmd) Execute(_ []string) error {
+	if cmd.Ref == "" && cmd.OldInput == "" && cmd.OldPoDir == "" {
+		return fmt.Errorf("nothing to compare: use --ref, --old-input or --old-podir")
+	}
+
+	report := &DiffReport{}
+
+	oldRows, err := cmd.loadOldCSV()
+	if err != nil {
+		return err
+	}
+	if oldRows != nil {
+		newRows, err := csvutil.LoadCSV(cmd.Input)
+		if err != nil {
+			return fmt.Errorf("failed to load CSV: %w", err)
+		}
+		report.Strings = diffStrings(oldRows, newRows)
+	}
+
+	oldPO, err := cmd.loadOldPO()
+	if err != nil {
+		return err
+	}
+	if oldPO != nil {
+		newPO, err := poutil.LoadPODirectory(cmd.PoDir)
+		if err != nil {
+			return fmt.Errorf("failed to load PO files: %w", err)
+		}
+		report.Languages = diffLanguages(oldPO, newPO, normalizeLangs(cmd.Langs))
+	}
+
+	switch cmd.Format {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "markdown":
+		fmt.Print(formatDiffMarkdown(report))
+	default:
+		fmt.Print(formatDiffText(report))
+	}
+	return nil
+}
+
+// loadOldCSV returns old CSV rows from --old-input or --ref, or nil if neither is set.
+func (cmd *DiffCmd) loadOldCSV() ([][]string, error) {
+	if cmd.OldInput != "" {
+		rows, err := csvutil.LoadCSV(cmd.OldInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load old CSV: %w", err)
+		}
+		return rows, nil
+	}
+	if cmd.Ref == "" {
+		return nil, nil
+	}
+
+	data, err := gitShow(cmd.Ref, cmd.Input)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := csvutil.ParseCSV(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s at %s: %w", cmd.Input, cmd.Ref, err)
+	}
+	return rows, nil
+}
+
+// loadOldPO returns old PO files from --old-podir or --ref, or nil if neither is set.
+func (cmd *DiffCmd) loadOldPO() (map[string]*poutil.File, error) {
+	if cmd.OldPoDir != "" {
+		poMap, err := poutil.LoadPODirectory(cmd.OldPoDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load old PO files: %w", err)
+		}
+		return poMap, nil
+	}
+	if cmd.Ref == "" {
+		return nil, nil
+	}
+
+	paths, err := gitListFiles(cmd.Ref, cmd.PoDir)
+	if err != nil {
+		return nil, err
+	}
+	poMap := make(map[string]*poutil.File)
+	for _, path := range paths {
+		if filepath.Ext(path) != ".po" {
+			continue
+		}
+		data, err := gitShow(cmd.Ref, path)
+		if err != nil {
+			return nil, err
+		}
+		po, err := poutil.ParseReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s at %s: %w", path, cmd.Ref, err)
+		}
+		poMap[ExtractLanguageName(path)] = po
+	}
+	return poMap, nil
+}
+
+// gitShow reads a file blob at the given revision.
+// The path is resolved relative to the current directory.
+func gitShow(ref, path string) ([]byte, error) {
+	rel, err := gitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := ref + ":./" + rel
+	out, err := runGit("show", spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", spec, err)
+	}
+	return out, nil
+}
+
+// gitListFiles lists files directly inside dir at the given revision.
+func gitListFiles(ref, dir string) ([]string, error) {
+	rel, err := gitPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	out, err := runGit("ls-tree", "--name-only", ref, "--", "./"+rel+"/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s at %s: %w", dir, ref, err)
+	}
+	var paths []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths, nil
+}
+
+// gitPath returns path relative to the current directory with forward
+// slashes, as git expects in revision paths. Absolute paths are made
+// relative first, paths outside the repository are an error.
+func gitPath(path string) (string, error) {
+	rel := filepath.Clean(path)
+	if filepath.IsAbs(rel) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+		if rel, err = filepath.Rel(wd, rel); err != nil {
+			return "", fmt.Errorf("%s is outside the git repository", path)
+		}
+	}
+	rel = filepath.ToSlash(rel)
+
+	// Prefix of the current directory in the repository, e.g. "mod/"
+	prefix, err := runGit("rev-parse", "--show-prefix")
+	if err != nil {
+		return "", err
+	}
+	full := filepath.ToSlash(filepath.Join(filepath.FromSlash(strings.TrimSpace(string(prefix))), filepath.FromSlash(rel)))
+	if full == ".." || strings.HasPrefix(full, "../") {
+		return "", fmt.Errorf("%s is outside the git repository", path)
+	}
+	return rel, nil
+}
+
+// runGit runs a git command in the current directory and returns its stdout.
+func runGit(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	c := exec.Command("git", args...) // #nosec G204 -- arguments are revisions and paths passed by the user
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// diffStrings compares keys and originals of two CSVs.
+// Added and changed strings follow the new CSV order, removed the old one.
+func diffStrings(oldRows, newRows [][]string) *StringsDiff {
+	diff := &StringsDiff{
+		Added:   []StringChange{},
+		Removed: []StringChange{},
+		Changed: []StringChange{},
+	}
+
+	oldMap := csvOriginals(oldRows)
+	newMap := csvOriginals(newRows)
+
+	for _, row := range newRows[1:] {
+		if len(row) < 2 {
+			continue
+		}
+		old, ok := oldMap[row[0]]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, StringChange{Key: row[0], New: row[1]})
+		case old != row[1]:
+			diff.Changed = append(diff.Changed, StringChange{Key: row[0], Old: old, New: row[1]})
+		}
+	}
+	for _, row := range oldRows[1:] {
+		if len(row) < 2 {
+			continue
+		}
+		if _, ok := newMap[row[0]]; !ok {
+			diff.Removed = append(diff.Removed, StringChange{Key: row[0], Old: row[1]})
+		}
+	}
+
+	return diff
+}
+
+// csvOriginals maps CSV keys to original texts.
+func csvOriginals(rows [][]string) map[string]string {
+	out := make(map[string]string, len(rows))
+	for _, row := range rows[1:] {
+		if len(row) >= 2 {
+			out[row[0]] = row[1]
+		}
+	}
+	return out
+}
+
+// diffLanguages compares msgstr values per language, keyed by msgctxt and
+// msgid, so an entry whose original changed is reported as cleared and
+// translated again rather than as a changed translation.
+func diffLanguages(oldPO, newPO map[string]*poutil.File, filter []string) map[string]*LangDiff {
+	langSet := make(map[string]*poutil.File, len(newPO))
+	for lang, po := range oldPO {
+		langSet[lang] = po
+	}
+	for lang, po := range newPO {
+		langSet[lang] = po
+	}
+
+	result := make(map[string]*LangDiff)
+	for _, lang := range selectLanguagesInOrder(langSet, filter) {
+		diff := &LangDiff{
+			Translated: []TranslationChange{},
+			Changed:    []TranslationChange{},
+			Cleared:    []TranslationChange{},
+		}
+
+		oldEntries := entriesByKey(oldPO[lang])
+		newEntries := entriesByKey(newPO[lang])
+
+		if po := newPO[lang]; po != nil {
+			for _, entry := range po.Entries {
+				oldStr := ""
+				if old := oldEntries[entry.Key()]; old != nil {
+					oldStr = old.MsgStr
+				}
+				change := TranslationChange{Key: entry.Context, Original: entry.MsgID, Old: oldStr, New: entry.MsgStr}
+				switch {
+				case oldStr == entry.MsgStr:
+				case oldStr == "":
+					diff.Translated = append(diff.Translated, change)
+				case entry.MsgStr == "":
+					diff.Cleared = append(diff.Cleared, change)
+				default:
+					diff.Changed = append(diff.Changed, change)
+				}
+			}
+		}
+		if po := oldPO[lang]; po != nil {
+			for _, entry := range po.Entries {
+				if _, ok := newEntries[entry.Key()]; ok || entry.MsgStr == "" {
+					continue
+				}
+				diff.Cleared = append(diff.Cleared, TranslationChange{Key: entry.Context, Original: entry.MsgID, Old: entry.MsgStr})
+			}
+		}
+
+		result[lang] = diff
+	}
+	return result
+}
+
+// entriesByKey indexes PO entries by Entry.Key, msgctxt and msgid.
+func entriesByKey(po *poutil.File) map[string]*poutil.Entry {
+	out := make(map[string]*poutil.Entry)
+	if po == nil {
+		return out
+	}
+	for _, entry := range po.Entries {
+		out[entry.Key()] = entry
+	}
+	return out
+}
+
+// sortedDiffLangs returns report languages in default order, then the rest sorted.
+func sortedDiffLangs(langs map[string]*LangDiff) []string {
+	var out, rest []string
+	for _, lang := range DefaultLanguages {
+		if _, ok := langs[lang]; ok {
+			out = append(out, lang)
+		}
+	}
+	for lang := range langs {
+		if !ContainsLanguage(DefaultLanguages, lang) {
+			rest = append(rest, lang)
+		}
+	}
+	sort.Strings(rest)
+	return append(out, rest...)
+}
+
+// formatDiffText renders the report as plain text.
+func formatDiffText(report *DiffReport) string {
+	var b strings.Builder
+	if s := report.Strings; s != nil {
+		fmt.Fprintf(&b, "strings: %d added, %d removed, %d changed\n", len(s.Added), len(s.Removed), len(s.Changed))
+		for _, c := range s.Added {
+			fmt.Fprintf(&b, "+ %s %q\n", c.Key, c.New)
+		}
+		for _, c := range s.Removed {
+			fmt.Fprintf(&b, "- %s %q\n", c.Key, c.Old)
+		}
+		for _, c := range s.Changed {
+			fmt.Fprintf(&b, "~ %s %q -> %q\n", c.Key, c.Old, c.New)
+		}
+	}
+	for _, lang := range sortedDiffLangs(report.Languages) {
+		d := report.Languages[lang]
+		fmt.Fprintf(&b, "lang %s: %d translated, %d changed, %d cleared\n", lang, len(d.Translated), len(d.Changed), len(d.Cleared))
+		for _, c := range d.Translated {
+			fmt.Fprintf(&b, "+ %s %q\n", c.Key, c.New)
+		}
+		for _, c := range d.Changed {
+			fmt.Fprintf(&b, "~ %s %q -> %q\n", c.Key, c.Old, c.New)
+		}
+		for _, c := range d.Cleared {
+			fmt.Fprintf(&b, "- %s %q\n", c.Key, c.Old)
+		}
+	}
+	return b.String()
+}
+
+// formatDiffMarkdown renders the report as Markdown for release changelogs.
+func formatDiffMarkdown(report *DiffReport) string {
+	var b strings.Builder
+	if s := report.Strings; s != nil {
+		b.WriteString("### Strings\n\n")
+		writeMarkdownStrings(&b, "Added", s.Added, func(c StringChange) string {
+			return fmt.Sprintf("`%s`: %s", c.Key, markdownText(c.New))
+		})
+		writeMarkdownStrings(&b, "Removed", s.Removed, func(c StringChange) string {
+			return fmt.Sprintf("`%s`: %s", c.Key, markdownText(c.Old))
+		})
+		writeMarkdownStrings(&b, "Changed", s.Changed, func(c StringChange) string {
+			return fmt.Sprintf("`%s`: %s &rarr; %s", c.Key, markdownText(c.Old), markdownText(c.New))
+		})
+		if len(s.Added)+len(s.Removed)+len(s.Changed) == 0 {
+			b.WriteString("No changes.\n\n")
+		}
+	}
+
+	if len(report.Languages) > 0 {
+		b.WriteString("### Translations\n\n")
+		b.WriteString("| Language | Translated | Changed | Cleared |\n")
+		b.WriteString("| -------- | ---------: | ------: | ------: |\n")
+		for _, lang := range sortedDiffLangs(report.Languages) {
+			d := report.Languages[lang]
+			fmt.Fprintf(&b, "| %s | %d | %d | %d |\n", lang, len(d.Translated), len(d.Changed), len(d.Cleared))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// writeMarkdownStrings writes a titled bullet list if items is not empty.
+func writeMarkdownStrings(b *strings.Builder, title string, items []StringChange, format func(StringChange) string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s:\n\n", title)
+	for _, item := range items {
+		fmt.Fprintf(b, "* %s\n", format(item))
+	}
+	b.WriteString("\n")
+}
+
+// markdownText quotes text for inline Markdown, keeping it on one line.
+func markdownText(s string) string {
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return "\"" + strings.ReplaceAll(s, "|", `\|`) + "\""
+}
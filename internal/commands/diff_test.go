@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+func TestDiffStrings(t *testing.T) {
+	oldRows := [][]string{
+		{"Language", "original"},
+		{"STR_A", "A"},
+		{"STR_B", "B"},
+	}
+	newRows := [][]string{
+		{"Language", "original"},
+		{"STR_A", "A2"},
+		{"STR_C", "C"},
+	}
+
+	diff := diffStrings(oldRows, newRows)
+
+	if len(diff.Added) != 1 || diff.Added[0].Key != "STR_C" {
+		t.Errorf("Added = %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Key != "STR_B" {
+		t.Errorf("Removed = %+v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Old != "A" || diff.Changed[0].New != "A2" {
+		t.Errorf("Changed = %+v", diff.Changed)
+	}
+}
+
+func TestDiffLanguages_IgnoresHeaders(t *testing.T) {
+	oldPo := poutil.NewFile()
+	oldPo.SetHeader("PO-Revision-Date", "2025-01-01 00:00+0000")
+	oldPo.SetHeader("X-Content-Hash", "0000000000000001")
+	oldPo.SetC("STR_A", "A", "")
+	oldPo.SetC("STR_B", "B", "Б")
+	oldPo.SetC("STR_C", "C", "Ц")
+	oldPo.SetC("STR_D", "D", "Д")
+
+	newPo := poutil.NewFile()
+	newPo.SetHeader("PO-Revision-Date", "2026-01-01 00:00+0000")
+	newPo.SetHeader("X-Content-Hash", "0000000000000002")
+	newPo.SetC("STR_A", "A", "А")
+	newPo.SetC("STR_B", "B", "Бэ")
+	newPo.SetC("STR_C", "C", "")
+	newPo.SetC("STR_D", "D", "Д")
+
+	result := diffLanguages(
+		map[string]*poutil.File{"russian": oldPo},
+		map[string]*poutil.File{"russian": newPo},
+		nil,
+	)
+
+	d := result["russian"]
+	if d == nil {
+		t.Fatal("russian diff missing")
+	}
+	if len(d.Translated) != 1 || d.Translated[0].Key != "STR_A" {
+		t.Errorf("Translated = %+v", d.Translated)
+	}
+	if len(d.Changed) != 1 || d.Changed[0].Key != "STR_B" {
+		t.Errorf("Changed = %+v", d.Changed)
+	}
+	if len(d.Cleared) != 1 || d.Cleared[0].Key != "STR_C" {
+		t.Errorf("Cleared = %+v", d.Cleared)
+	}
+
+	md := formatDiffMarkdown(&DiffReport{Languages: result})
+	if !strings.Contains(md, "| russian | 1 | 1 | 1 |") {
+		t.Errorf("unexpected markdown:\n%s", md)
+	}
+}
+
+func TestDiffLanguages_ChangedOriginal(t *testing.T) {
+	oldPo := poutil.NewFile()
+	oldPo.SetC("STR_A", "Open", "Открыть")
+
+	newPo := poutil.NewFile()
+	newPo.SetC("STR_A", "Open door", "Открыть дверь")
+
+	d := diffLanguages(
+		map[string]*poutil.File{"russian": oldPo},
+		map[string]*poutil.File{"russian": newPo},
+		nil,
+	)["russian"]
+
+	if len(d.Changed) != 0 {
+		t.Errorf("Changed = %+v, want none for a changed original", d.Changed)
+	}
+	if len(d.Translated) != 1 || d.Translated[0].Original != "Open door" {
+		t.Errorf("Translated = %+v", d.Translated)
+	}
+	if len(d.Cleared) != 1 || d.Cleared[0].Original != "Open" {
+		t.Errorf("Cleared = %+v", d.Cleared)
+	}
+}
+
+// TestDiffCmd_Ref verifies comparing a git commit against the working tree.
+func TestDiffCmd_Ref(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	repo := t.TempDir()
+	t.Chdir(repo)
+	git := func(args ...string) {
+		t.Helper()
+		c := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, out)
+		}
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+	writePo := func(pairs ...string) {
+		t.Helper()
+		po := poutil.NewFile()
+		for i := 0; i+2 < len(pairs); i += 3 {
+			po.SetC(pairs[i], pairs[i+1], pairs[i+2])
+		}
+		data, err := po.MarshalText()
+		if err != nil {
+			t.Fatalf("failed to marshal po: %v", err)
+		}
+		write(filepath.Join("l18n", "russian.po"), string(data))
+	}
+
+	git("init", "-q")
+	write("stringtable.csv", "\"Language\",\"original\"\n\"STR_Yes\",\"Yes\"\n")
+	writePo("STR_Yes", "Yes", "")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	write("stringtable.csv", "\"Language\",\"original\"\n\"STR_Yes\",\"Yes\"\n\"STR_No\",\"No\"\n")
+	writePo("STR_Yes", "Yes", "Да", "STR_No", "No", "")
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	cmd := &DiffCmd{Input: "stringtable.csv", PoDir: "l18n", Ref: "HEAD", Format: "json"}
+	err := cmd.Execute(nil)
+	_ = w.Close()
+	os.Stdout = oldStdout
+	if err != nil {
+		t.Fatalf("DiffCmd.Execute failed: %v", err)
+	}
+	out, _ := io.ReadAll(r)
+
+	var report DiffReport
+	if err := json.Unmarshal(out, &report); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\n%s", err, out)
+	}
+	if report.Strings == nil || len(report.Strings.Added) != 1 || report.Strings.Added[0].Key != "STR_No" {
+		t.Errorf("Strings = %+v", report.Strings)
+	}
+	d := report.Languages["russian"]
+	if d == nil || len(d.Translated) != 1 || d.Translated[0].Key != "STR_Yes" || d.Translated[0].New != "Да" {
+		t.Errorf("russian = %+v", d)
+	}
+
+	// Absolute paths inside the repository work, paths outside fail clearly
+	abs := &DiffCmd{Input: filepath.Join(repo, "stringtable.csv"), PoDir: filepath.Join(repo, "l18n"), Ref: "HEAD"}
+	if _, err := abs.loadOldCSV(); err != nil {
+		t.Errorf("absolute --input failed: %v", err)
+	}
+	if poMap, err := abs.loadOldPO(); err != nil || poMap["russian"] == nil {
+		t.Errorf("absolute --podir failed: %v, %v", poMap, err)
+	}
+	outside := &DiffCmd{Input: filepath.Join(filepath.Dir(repo), "stringtable.csv"), Ref: "HEAD"}
+	if _, err := outside.loadOldCSV(); err == nil || !strings.Contains(err.Error(), "outside the git repository") {
+		t.Errorf("expected error for a path outside the repository, got %v", err)
+	}
+}
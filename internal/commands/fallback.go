@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// fallbackChains maps a language to the languages tried, in order, when it
+// has no translation for a string. The original text is always the last
+// fallback and is not part of a chain.
+type fallbackChains map[string][]string
+
+// parseFallbacks parses --fallback options in the form
+// "lang:fallback[,fallback...]" as collected by go-flags into a map.
+func parseFallbacks(specs map[string]string) (fallbackChains, error) {
+	chains := make(fallbackChains, len(specs))
+	for lang, list := range specs {
+		lang = strings.TrimSpace(lang)
+		if lang == "" {
+			return nil, fmt.Errorf("fallback for an empty language")
+		}
+
+		for _, fallback := range strings.Split(list, ",") {
+			fallback = strings.TrimSpace(fallback)
+			switch fallback {
+			case "":
+				return nil, fmt.Errorf("empty fallback language for '%s'", lang)
+			case lang:
+				return nil, fmt.Errorf("language '%s' can't fall back to itself", lang)
+			case "original":
+				// Implicit end of every chain
+				continue
+			}
+			chains[lang] = append(chains[lang], fallback)
+		}
+	}
+	return chains, nil
+}
+
+// chain returns the fallback languages of lang, following the chains of
+// fallback languages as well (chinesesimp -> chinese -> japanese).
+// Every language appears once, so cyclic chains are cut.
+func (c fallbackChains) chain(lang string) []string {
+	var out []string
+	seen := map[string]bool{lang: true}
+
+	var walk func(l string)
+	walk = func(l string) {
+		for _, fallback := range c[l] {
+			if seen[fallback] {
+				continue
+			}
+			seen[fallback] = true
+			out = append(out, fallback)
+			walk(fallback)
+		}
+	}
+	walk(lang)
+
+	return out
+}
+
+// entryFilter returns the text of a PO entry as a translation, or
+// ok=false if a command handles the entry as untranslated.
+type entryFilter func(entry *poutil.Entry, original string) (text string, ok bool)
+
+// resolve returns the translation of a string from the first fallback
+// language of lang that has one accepted by translated, and that language.
+// Returns ok=false if no fallback language has a translation.
+func (c fallbackChains) resolve(poMap map[string]*poutil.File, lang, key, original string, translated entryFilter) (text, from string, ok bool) {
+	for _, fallback := range c.chain(lang) {
+		po := poMap[fallback]
+		if po == nil {
+			continue
+		}
+		if text, ok := translated(po.GetEntry(key, original), original); ok {
+			return text, fallback, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFallbacks(t *testing.T) {
+	chains, err := parseFallbacks(map[string]string{
+		"chinesesimp": "chinese, original",
+		"czech":       "polish,slovak",
+	})
+	if err != nil {
+		t.Fatalf("parseFallbacks failed: %v", err)
+	}
+	want := fallbackChains{
+		"chinesesimp": {"chinese"},
+		"czech":       {"polish", "slovak"},
+	}
+	if !reflect.DeepEqual(chains, want) {
+		t.Errorf("parseFallbacks = %v, want %v", chains, want)
+	}
+
+	for _, spec := range []map[string]string{
+		{"czech": "czech"},
+		{"czech": "polish,"},
+	} {
+		if _, err := parseFallbacks(spec); err == nil {
+			t.Errorf("expected error for %v", spec)
+		}
+	}
+}
+
+func TestFallbackChains_Chain(t *testing.T) {
+	chains := fallbackChains{
+		"chinesesimp": {"chinese"},
+		"chinese":     {"japanese", "chinesesimp"},
+		"japanese":    {"chinese"},
+	}
+	if got, want := chains.chain("chinesesimp"), []string{"chinese", "japanese"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("chain(chinesesimp) = %v, want %v", got, want)
+	}
+	if got := chains.chain("russian"); len(got) != 0 {
+		t.Errorf("chain(russian) = %v, want empty", got)
+	}
+}
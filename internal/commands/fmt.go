@@ -0,0 +1,97 @@
+package commands
+
+//lint:file-ignore SA5008 go-flags requires duplicate choice tags on struct fields
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/woozymasta/dayz-stringtable/internal/csvutil"
+)
+
+// FmtCmd rewrites a CSV file in the canonical style produced by make:
+// every field quoted, trailing comma, fixed line endings.
+//
+// Usage: dayz-stringtable fmt --input stringtable.csv [--output out.csv] [--eol crlf] [--sort key|prefix] [--check]
+type FmtCmd struct {
+	Input  string `short:"i" long:"input" description:"CSV file to format" default:"stringtable.csv"`
+	Output string `short:"o" long:"output" description:"Formatted CSV output (rewrites --input if empty)"`
+	EOL    string `short:"e" long:"eol" description:"Line ending" default:"lf" choice:"lf" choice:"crlf"`
+	Sort   string `short:"s" long:"sort" description:"Sort data rows by key or by key prefix group" default:"none" choice:"none" choice:"key" choice:"prefix"`
+	Check  bool   `short:"c" long:"check" description:"Don't write, exit with error if the file is not formatted"`
+}
+
+// Execute formats the CSV file or checks that it is already formatted.
+func (cmd *FmtCmd) Execute(_ []string) error {
+	original, err := os.ReadFile(cmd.Input)
+	if err != nil {
+		return fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	rows, err := csvutil.LoadCSV(cmd.Input)
+	if err != nil {
+		return fmt.Errorf("failed to load CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("CSV must have a header row")
+	}
+
+	sortCSVRows(rows, cmd.Sort)
+
+	eol := "\n"
+	if cmd.EOL == "crlf" {
+		eol = "\r\n"
+	}
+	data := marshalCSVRows(rows, eol)
+
+	if cmd.Check {
+		if !bytes.Equal(original, data) {
+			return fmt.Errorf("%s is not formatted, run 'dayz-stringtable fmt'", cmd.Input)
+		}
+		return nil
+	}
+
+	output := cmd.Output
+	if output == "" {
+		output = cmd.Input
+	}
+	if output == cmd.Input && bytes.Equal(original, data) {
+		return nil
+	}
+	if err := csvutil.WriteFile(output, data, true); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+// sortCSVRows sorts data rows in place, keeping the header first.
+// Mode "key" sorts by key, "prefix" groups keys by prefix (the key up to
+// its last underscore) and keeps the original order inside each group.
+func sortCSVRows(rows [][]string, mode string) {
+	if len(rows) < 3 {
+		return
+	}
+	data := rows[1:]
+
+	switch mode {
+	case "key":
+		sort.SliceStable(data, func(i, j int) bool {
+			return data[i][0] < data[j][0]
+		})
+	case "prefix":
+		sort.SliceStable(data, func(i, j int) bool {
+			return keyPrefix(data[i][0]) < keyPrefix(data[j][0])
+		})
+	}
+}
+
+// keyPrefix returns the key up to (not including) its last underscore.
+func keyPrefix(key string) string {
+	if idx := strings.LastIndex(key, "_"); idx > 0 {
+		return key[:idx]
+	}
+	return key
+}
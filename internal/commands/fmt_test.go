@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFmtCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	csvPath := filepath.Join(tmpDir, "stringtable.csv")
+	messy := "\ufeff\"Language\",original,russian\r\n" +
+		"STR_B_Two,Two,\r\n" +
+		"STR_A_One,\"One, \"\"quoted\"\"\",Один\r\n" +
+		"STR_B_One,One,\r\n"
+	if err := os.WriteFile(csvPath, []byte(messy), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	check := &FmtCmd{Input: csvPath, EOL: "lf", Sort: "none", Check: true}
+	if err := check.Execute(nil); err == nil {
+		t.Fatal("expected --check to fail on unformatted file")
+	}
+
+	cmd := &FmtCmd{Input: csvPath, EOL: "lf", Sort: "prefix"}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("FmtCmd.Execute failed: %v", err)
+	}
+
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	expected := `"Language","original","russian",
+"STR_A_One","One, ""quoted""","Один",
+"STR_B_Two","Two","",
+"STR_B_One","One","",
+`
+	if string(data) != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", data, expected)
+	}
+
+	check.Sort = "prefix"
+	if err := check.Execute(nil); err != nil {
+		t.Errorf("expected --check to pass on formatted file: %v", err)
+	}
+}
+
+func TestFmtCmd_CRLFAndKeySort(t *testing.T) {
+	tmpDir := t.TempDir()
+	csvPath := filepath.Join(tmpDir, "stringtable.csv")
+	content := `"Language","original",
+"STR_B","B",
+"STR_A","A",
+`
+	if err := os.WriteFile(csvPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	outPath := filepath.Join(tmpDir, "out.csv")
+	cmd := &FmtCmd{Input: csvPath, Output: outPath, EOL: "crlf", Sort: "key"}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("FmtCmd.Execute failed: %v", err)
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	expected := "\"Language\",\"original\",\r\n\"STR_A\",\"A\",\r\n\"STR_B\",\"B\",\r\n"
+	if string(data) != expected {
+		t.Errorf("unexpected output:\n%q\nexpected:\n%q", data, expected)
+	}
+}
@@ -25,6 +25,34 @@ var DefaultLanguages = []string{
 	"chinesesimp",
 }
 
+// languageCodes maps DayZ language names to ISO 639-1 codes with a
+// region for languages that share one.
+var languageCodes = map[string]string{
+	"english":     "en",
+	"czech":       "cs",
+	"german":      "de",
+	"russian":     "ru",
+	"polish":      "pl",
+	"hungarian":   "hu",
+	"italian":     "it",
+	"spanish":     "es",
+	"french":      "fr",
+	"chinese":     "zh-tw",
+	"japanese":    "ja",
+	"portuguese":  "pt",
+	"chinesesimp": "zh-cn",
+}
+
+// LanguageCode returns the ISO code of a DayZ language name, or the
+// lowercased name for unknown languages.
+func LanguageCode(lang string) string {
+	lang = strings.ToLower(lang)
+	if code, ok := languageCodes[lang]; ok {
+		return code
+	}
+	return lang
+}
+
 // ParseLanguages parses a comma-separated string of languages into a slice.
 func ParseLanguages(langsStr string) []string {
 	if langsStr == "" {
@@ -1,21 +1,32 @@
 package commands
 
+//lint:file-ignore SA5008 go-flags requires duplicate choice tags on struct fields
+
 import (
 	"fmt"
 	"strings"
 
 	"github.com/woozymasta/dayz-stringtable/internal/csvutil"
 	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+	"github.com/woozymasta/dayz-stringtable/internal/pseudo"
 )
 
 // MakeCmd merges PO files back into a CSV file with translations.
 //
-// Usage: dayz-stringtable make --input stringtable.csv --podir po/ --output full.csv [--force]
+// Usage: dayz-stringtable make --input stringtable.csv --podir po/ --output full.csv [--force] [--fallback chinesesimp:chinese] [--untranslated marker] [--pseudo english] [--check]
 type MakeCmd struct {
-	Input  string `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
-	PoDir  string `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
-	Output string `short:"o" long:"output" description:"Merged CSV output (stdout if empty)"`
-	Force  bool   `short:"f" long:"force" description:"Overwrite existing files"`
+	Input            string            `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
+	PoDir            string            `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
+	Output           string            `short:"o" long:"output" description:"Merged CSV output (stdout if empty)"`
+	Fallbacks        map[string]string `short:"F" long:"fallback" description:"Languages to use before the original text, as lang:fallback[,fallback] (repeatable)"`
+	Untranslated     string            `short:"u" long:"untranslated" description:"Text of untranslated strings: fallback text, empty, the key, or fallback text with a [!lang] marker" default:"original" choice:"original" choice:"empty" choice:"key" choice:"marker"`
+	Fuzzy            string            `long:"fuzzy" description:"Use msgstr of fuzzy entries or handle them as untranslated" default:"translation" choice:"translation" choice:"untranslated"`
+	NoTranslate      string            `long:"notranslate" description:"Use original text for notranslate entries or handle them as untranslated" default:"original" choice:"original" choice:"untranslated"`
+	Pseudo           string            `long:"pseudo" description:"Replace the column of this language with pseudo-localized originals for test builds"`
+	PseudoTransforms []string          `long:"pseudo-transform" description:"Pseudo-localization transforms (repeatable)" default:"accents" default:"expand" default:"brackets" choice:"accents" choice:"expand" choice:"brackets" choice:"mirror" choice:"rtl"`
+	PseudoExpansion  int               `long:"pseudo-expansion" description:"Text expansion of the expand transform in percent" default:"30"`
+	Force            bool              `short:"f" long:"force" description:"Overwrite existing files"`
+	Check            bool              `short:"c" long:"check" description:"Don't write, exit with error if --output is not up to date"`
 }
 
 // Execute loads CSV and PO files, then writes a merged CSV with all translations.
@@ -30,13 +41,26 @@ func (cmd *MakeCmd) Execute(_ []string) error {
 		return fmt.Errorf("failed to load PO files: %w", err)
 	}
 
-	// Determine languages in default order
+	fallbacks, err := parseFallbacks(cmd.Fallbacks)
+	if err != nil {
+		return err
+	}
+
+	pseudoOpts, err := pseudo.ParseOptions(cmd.PseudoTransforms, cmd.PseudoExpansion)
+	if err != nil {
+		return err
+	}
+
+	// Determine languages in default order, the pseudo language needs no PO file
 	var langs []string
 	for _, l := range DefaultLanguages {
-		if _, ok := poMap[l]; ok {
+		if _, ok := poMap[l]; ok || l == cmd.Pseudo {
 			langs = append(langs, l)
 		}
 	}
+	if cmd.Pseudo != "" && !ContainsLanguage(langs, cmd.Pseudo) {
+		langs = append(langs, cmd.Pseudo)
+	}
 
 	var b strings.Builder
 	header := append([]string{"Language", "original"}, langs...)
@@ -52,35 +76,70 @@ func (cmd *MakeCmd) Execute(_ []string) error {
 		key := row[0]
 		original := row[1]
 		for _, l := range langs {
+			if l == cmd.Pseudo {
+				rec = append(rec, pseudoOpts.Apply(original))
+				continue
+			}
 			poFile := poMap[l]
 			entry := poFile.GetEntry(key, original)
-			var translation string
-			if entry != nil {
-				// Check if entry has notranslate flag
-				if entry.HasNoTranslate() {
-					translation = original // Use original as fallback
-				} else {
-					translation = entry.MsgStr
-					// If translation is empty, use original as fallback
-					if translation == "" {
-						translation = original
-					}
-				}
-			} else {
-				// Entry not found, use original as fallback
-				translation = original
+			translation, ok := cmd.entryTranslation(entry, original)
+			if !ok {
+				translation = cmd.untranslatedText(poMap, fallbacks, l, key, original)
 			}
 			rec = append(rec, translation)
 		}
 		writeQuotedCSVRow(&b, rec)
 	}
 
+	if cmd.Check {
+		return checkUpToDate(cmd.Output, []byte(b.String()), "make")
+	}
+
 	if err := csvutil.WriteFile(cmd.Output, []byte(b.String()), cmd.Force); err != nil {
 		return fmt.Errorf("failed to write output: %w", err)
 	}
 	return nil
 }
 
+// entryTranslation returns the text of a PO entry for the output CSV.
+// Returns ok=false if the entry is missing, empty, or handled as
+// untranslated by the --fuzzy and --notranslate modes.
+func (cmd *MakeCmd) entryTranslation(entry *poutil.Entry, original string) (string, bool) {
+	switch {
+	case entry == nil:
+		return "", false
+	case entry.HasNoTranslate():
+		// Entry has notranslate flag, original is the translation
+		return original, cmd.NoTranslate != "untranslated"
+	case entry.MsgStr == "":
+		return "", false
+	case cmd.Fuzzy == "untranslated" && entry.HasFlag("fuzzy"):
+		return "", false
+	}
+	return entry.MsgStr, true
+}
+
+// untranslatedText returns the text of an untranslated string according to
+// the --untranslated mode. The fallback text is the translation of the first
+// fallback language that has one, or the original text.
+func (cmd *MakeCmd) untranslatedText(poMap map[string]*poutil.File, fallbacks fallbackChains, lang, key, original string) string {
+	switch cmd.Untranslated {
+	case "empty":
+		return ""
+	case "key":
+		return key
+	}
+
+	text := original
+	if fallback, _, ok := fallbacks.resolve(poMap, lang, key, original, cmd.entryTranslation); ok {
+		text = fallback
+	}
+	if cmd.Untranslated == "marker" {
+		return fmt.Sprintf("[!%s] %s", LanguageCode(lang), text)
+	}
+	return text
+}
+
 // writeQuotedCSVRow writes a CSV row with proper quoting and escaping.
 func writeQuotedCSVRow(b *strings.Builder, fields []string) {
 	for _, f := range fields {
@@ -89,3 +148,29 @@ func writeQuotedCSVRow(b *strings.Builder, fields []string) {
 	}
 	b.WriteByte('\n')
 }
+
+// marshalCSVRows serializes rows with writeQuotedCSVRow using eol as line ending.
+// A trailing header column with an empty name is the artifact of trailing
+// commas in the source file and is dropped, as it would be re-added by
+// the trailing comma of every written row.
+func marshalCSVRows(rows [][]string, eol string) []byte {
+	width := 0
+	if len(rows) > 0 {
+		width = len(rows[0])
+		if width > 0 && rows[0][width-1] == "" {
+			width--
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		if len(row) > width {
+			row = row[:width]
+		}
+		var line strings.Builder
+		writeQuotedCSVRow(&line, row)
+		b.WriteString(strings.TrimSuffix(line.String(), "\n"))
+		b.WriteString(eol)
+	}
+	return []byte(b.String())
+}
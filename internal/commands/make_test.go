@@ -151,3 +151,306 @@ func TestMakeCmdFallback(t *testing.T) {
 		t.Errorf("unexpected output:\n%s\nexpected:\n%s", string(outData), expected)
 	}
 }
+
+// TestMakeCmdStripsMetadataColumns verifies that translator metadata
+// columns never reach the shipped CSV.
+func TestMakeCmdStripsMetadataColumns(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	csvContent := `"Language","original","comment","maxlen","russian",
+"STR_Title","Title","Menu title","24","",
+`
+	csvPath := filepath.Join(tmpDir, "input.csv")
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	poDir := filepath.Join(tmpDir, "po")
+	if err := os.Mkdir(poDir, 0o755); err != nil {
+		t.Fatalf("failed to create po dir: %v", err)
+	}
+	po := poutil.NewFile()
+	po.Language = "russian"
+	po.SetHeader("Language", "russian")
+	po.SetC("STR_Title", "Title", "Заголовок")
+	poData, err := po.MarshalText()
+	if err != nil {
+		t.Fatalf("failed to marshal po: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(poDir, "russian.po"), poData, 0o644); err != nil {
+		t.Fatalf("failed to write russian.po: %v", err)
+	}
+
+	outputPath := filepath.Join(tmpDir, "full.csv")
+	cmd := MakeCmd{Input: csvPath, PoDir: poDir, Output: outputPath}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("MakeCmd.Execute failed: %v", err)
+	}
+
+	outData, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	expected :=
+		`"Language","original","russian",
+"STR_Title","Title","Заголовок",
+`
+	if string(outData) != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", string(outData), expected)
+	}
+}
+
+// TestMakeCmdFallbackChain verifies that untranslated strings are taken from
+// fallback languages before the original text.
+func TestMakeCmdFallbackChain(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	csvContent := `"Language","original"
+"STR_Yes","Yes"
+"STR_No","No"
+"STR_Ok","OK"
+`
+	csvPath := filepath.Join(tmpDir, "input.csv")
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	poDir := filepath.Join(tmpDir, "po")
+	if err := os.Mkdir(poDir, 0o755); err != nil {
+		t.Fatalf("failed to create po dir: %v", err)
+	}
+	writeLang := func(lang string, pairs ...string) {
+		po := poutil.NewFile()
+		po.Language = lang
+		po.SetHeader("Language", lang)
+		for i := 0; i+2 < len(pairs); i += 3 {
+			po.SetC(pairs[i], pairs[i+1], pairs[i+2])
+		}
+		data, err := po.MarshalText()
+		if err != nil {
+			t.Fatalf("failed to marshal %s.po: %v", lang, err)
+		}
+		if err := os.WriteFile(filepath.Join(poDir, lang+".po"), data, 0o644); err != nil {
+			t.Fatalf("failed to write %s.po: %v", lang, err)
+		}
+	}
+	writeLang("chinese", "STR_Yes", "Yes", "是", "STR_No", "No", "")
+	writeLang("japanese", "STR_No", "No", "いいえ")
+	writeLang("chinesesimp", "STR_Yes", "Yes", "")
+
+	outputPath := filepath.Join(tmpDir, "full.csv")
+	cmd := MakeCmd{
+		Input:     csvPath,
+		PoDir:     poDir,
+		Output:    outputPath,
+		Fallbacks: map[string]string{"chinesesimp": "chinese", "chinese": "japanese,original"},
+	}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("MakeCmd.Execute failed: %v", err)
+	}
+
+	outData, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	expected := `"Language","original","chinese","japanese","chinesesimp",
+"STR_Yes","Yes","是","Yes","是",
+"STR_No","No","いいえ","いいえ","いいえ",
+"STR_Ok","OK","OK","OK","OK",
+`
+	if string(outData) != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", string(outData), expected)
+	}
+}
+
+// TestMakeCmdUntranslatedModes verifies --untranslated, --fuzzy and --notranslate modes.
+func TestMakeCmdUntranslatedModes(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	csvContent := `"Language","original"
+"STR_Yes","Yes"
+"STR_No","No"
+"STR_Ok","OK"
+"STR_Dayz","DayZ"
+`
+	csvPath := filepath.Join(tmpDir, "input.csv")
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	poDir := filepath.Join(tmpDir, "po")
+	if err := os.Mkdir(poDir, 0o755); err != nil {
+		t.Fatalf("failed to create po dir: %v", err)
+	}
+	po := poutil.NewFile()
+	po.Language = "russian"
+	po.SetHeader("Language", "russian")
+	po.Entries = append(po.Entries,
+		&poutil.Entry{Context: "STR_Yes", MsgID: "Yes", MsgStr: "Да"},
+		&poutil.Entry{Context: "STR_No", MsgID: "No", MsgStr: "Нет", Comments: []string{"#, fuzzy"}},
+		&poutil.Entry{Context: "STR_Dayz", MsgID: "DayZ", Comments: []string{"# notranslate"}},
+	)
+	data, err := po.MarshalText()
+	if err != nil {
+		t.Fatalf("failed to marshal po: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(poDir, "russian.po"), data, 0o644); err != nil {
+		t.Fatalf("failed to write russian.po: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		untranslated string
+		fuzzy        string
+		notranslate  string
+		want         []string // STR_Yes, STR_No, STR_Ok, STR_Dayz
+	}{
+		{"original", "original", "translation", "original", []string{"Да", "Нет", "OK", "DayZ"}},
+		{"empty", "empty", "translation", "original", []string{"Да", "Нет", "", "DayZ"}},
+		{"key", "key", "translation", "original", []string{"Да", "Нет", "STR_Ok", "DayZ"}},
+		{"marker", "marker", "translation", "original", []string{"Да", "Нет", "[!ru] OK", "DayZ"}},
+		{"marker fuzzy", "marker", "untranslated", "original", []string{"Да", "[!ru] No", "[!ru] OK", "DayZ"}},
+		{"marker notranslate", "marker", "translation", "untranslated", []string{"Да", "Нет", "[!ru] OK", "[!ru] DayZ"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "full.csv")
+			cmd := MakeCmd{
+				Input:        csvPath,
+				PoDir:        poDir,
+				Output:       outputPath,
+				Untranslated: tt.untranslated,
+				Fuzzy:        tt.fuzzy,
+				NoTranslate:  tt.notranslate,
+			}
+			if err := cmd.Execute(nil); err != nil {
+				t.Fatalf("MakeCmd.Execute failed: %v", err)
+			}
+
+			outData, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			expected := `"Language","original","russian",
+"STR_Yes","Yes","` + tt.want[0] + `",
+"STR_No","No","` + tt.want[1] + `",
+"STR_Ok","OK","` + tt.want[2] + `",
+"STR_Dayz","DayZ","` + tt.want[3] + `",
+`
+			if string(outData) != expected {
+				t.Errorf("unexpected output:\n%s\nexpected:\n%s", string(outData), expected)
+			}
+		})
+	}
+}
+
+// TestMakeCmdFallbackFuzzy verifies that fallback languages follow the
+// --fuzzy mode, so a fuzzy fallback translation is not used.
+func TestMakeCmdFallbackFuzzy(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	csvPath := filepath.Join(tmpDir, "input.csv")
+	if err := os.WriteFile(csvPath, []byte("\"Language\",\"original\"\n\"STR_Yes\",\"Yes\"\n\"STR_No\",\"No\"\n"), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+	poDir := filepath.Join(tmpDir, "po")
+	if err := os.Mkdir(poDir, 0o755); err != nil {
+		t.Fatalf("failed to create po dir: %v", err)
+	}
+	po := poutil.NewFile()
+	po.Language = "chinese"
+	po.SetHeader("Language", "chinese")
+	po.Entries = append(po.Entries,
+		&poutil.Entry{Context: "STR_Yes", MsgID: "Yes", MsgStr: "是"},
+		&poutil.Entry{Context: "STR_No", MsgID: "No", MsgStr: "不", Comments: []string{"#, fuzzy"}},
+	)
+	data, err := po.MarshalText()
+	if err != nil {
+		t.Fatalf("failed to marshal po: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(poDir, "chinese.po"), data, 0o644); err != nil {
+		t.Fatalf("failed to write chinese.po: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(poDir, "chinesesimp.po"), []byte(""), 0o644); err != nil {
+		t.Fatalf("failed to write chinesesimp.po: %v", err)
+	}
+
+	outputPath := filepath.Join(tmpDir, "full.csv")
+	cmd := MakeCmd{
+		Input:        csvPath,
+		PoDir:        poDir,
+		Output:       outputPath,
+		Fallbacks:    map[string]string{"chinesesimp": "chinese"},
+		Untranslated: "marker",
+		Fuzzy:        "untranslated",
+	}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("MakeCmd.Execute failed: %v", err)
+	}
+
+	outData, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	expected := `"Language","original","chinese","chinesesimp",
+"STR_Yes","Yes","是","[!zh-cn] 是",
+"STR_No","No","[!zh-tw] No","[!zh-cn] No",
+`
+	if string(outData) != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", string(outData), expected)
+	}
+}
+
+// TestMakeCmdCheck verifies that --check reports an outdated output and
+// that an unchanged output is not rewritten.
+func TestMakeCmdCheck(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	csvPath := filepath.Join(tmpDir, "input.csv")
+	if err := os.WriteFile(csvPath, []byte("\"Language\",\"original\"\n\"STR_Yes\",\"Yes\"\n"), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+	poDir := filepath.Join(tmpDir, "po")
+	if err := os.Mkdir(poDir, 0o755); err != nil {
+		t.Fatalf("failed to create po dir: %v", err)
+	}
+	writeRussian := func(msgstr string) {
+		po := poutil.NewFile()
+		po.Language = "russian"
+		po.SetHeader("Language", "russian")
+		po.SetC("STR_Yes", "Yes", msgstr)
+		data, err := po.MarshalText()
+		if err != nil {
+			t.Fatalf("failed to marshal po: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(poDir, "russian.po"), data, 0o644); err != nil {
+			t.Fatalf("failed to write russian.po: %v", err)
+		}
+	}
+	writeRussian("Да")
+
+	outputPath := filepath.Join(tmpDir, "full.csv")
+	check := MakeCmd{Input: csvPath, PoDir: poDir, Output: outputPath, Check: true}
+	if err := check.Execute(nil); err == nil {
+		t.Fatal("expected error for missing output")
+	}
+
+	cmd := MakeCmd{Input: csvPath, PoDir: poDir, Output: outputPath}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("MakeCmd.Execute failed: %v", err)
+	}
+	if err := check.Execute(nil); err != nil {
+		t.Fatalf("expected up to date output, got: %v", err)
+	}
+
+	// Without --force an unchanged output is not an error, it is left as is
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("expected unchanged output to be accepted without --force: %v", err)
+	}
+
+	writeRussian("Ага")
+	if err := check.Execute(nil); err == nil {
+		t.Fatal("expected error for outdated output")
+	}
+}
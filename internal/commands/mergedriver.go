@@ -0,0 +1,244 @@
+package commands
+
+//lint:file-ignore SA5008 go-flags requires duplicate choice tags on struct fields
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// mergeDriverName is the git merge driver name used in .gitattributes and git config.
+const mergeDriverName = "dayz-stringtable"
+
+// MergeDriverCmd is a git merge driver for PO files. It merges entries
+// instead of lines, so concurrent edits of different entries never conflict.
+//
+// Usage: dayz-stringtable merge-driver [--conflict markers|fuzzy] BASE OURS THEIRS
+type MergeDriverCmd struct {
+	Install  MergeDriverInstallCmd `command:"install" description:"Register the merge driver in .gitattributes and git config"`
+	Conflict string                `short:"c" long:"conflict" description:"How to leave conflicting entries" default:"markers" choice:"markers" choice:"fuzzy"`
+}
+
+// Execute merges THEIRS into OURS using BASE as the common ancestor.
+// Arguments are passed as plain args (not go-flags positional args),
+// otherwise the optional install subcommand could not be selected.
+// With conflict markers it exits with an error so git reports the conflict,
+// fuzzy conflicts only produce a warning.
+func (cmd *MergeDriverCmd) Execute(args []string) error {
+	if len(args) != 3 {
+		return fmt.Errorf("expected BASE OURS THEIRS (%%O %%A %%B), or use 'merge-driver install'")
+	}
+	basePath, oursPath, theirsPath := args[0], args[1], args[2]
+
+	base, err := parseMergeInput(basePath)
+	if err != nil {
+		return err
+	}
+	ours, err := parseMergeInput(oursPath)
+	if err != nil {
+		return err
+	}
+	theirs, err := parseMergeInput(theirsPath)
+	if err != nil {
+		return err
+	}
+
+	merged, conflicts := poutil.Merge3(base, ours, theirs)
+
+	// Recompute hash and revision date against the ours version
+	merged.SetHeader("X-Content-Hash", ours.GetHeader("X-Content-Hash"))
+	merged.UpdateBuildHeaders("")
+
+	var data []byte
+	if cmd.Conflict == "fuzzy" {
+		markFuzzyConflicts(merged, conflicts)
+		data, err = merged.MarshalText()
+		if err != nil {
+			return fmt.Errorf("marshal: %w", err)
+		}
+	} else {
+		data, err = marshalWithConflictMarkers(merged, conflicts)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := os.WriteFile(oursPath, data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", oursPath, err)
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+	if cmd.Conflict == "fuzzy" {
+		fmt.Fprintf(os.Stderr, "WARN: %d conflicting entries marked fuzzy in %s\n", len(conflicts), oursPath)
+		return nil
+	}
+	return fmt.Errorf("%d conflicting entries in %s", len(conflicts), oursPath)
+}
+
+// parseMergeInput parses a merge driver input file, an empty file
+// (e.g. missing ancestor) is treated as a PO file without entries.
+func parseMergeInput(path string) (*poutil.File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return poutil.NewFile(), nil
+	}
+	po, err := poutil.ParseReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return po, nil
+}
+
+// markFuzzyConflicts flags conflicting entries of the merged file as fuzzy
+// and records both candidates in translator comments.
+func markFuzzyConflicts(merged *poutil.File, conflicts []poutil.Conflict) {
+	for _, c := range conflicts {
+		candidate := c.Ours
+		if candidate == nil {
+			candidate = c.Theirs
+		}
+		entry := merged.GetEntry(candidate.Context, candidate.MsgID)
+		if entry == nil {
+			continue
+		}
+		notes := []string{
+			"# merge ours: " + conflictCandidate(c.Ours),
+			"# merge theirs: " + conflictCandidate(c.Theirs),
+		}
+		entry.Comments = append(notes, entry.Comments...)
+		entry.AddFlag("fuzzy")
+	}
+}
+
+// conflictCandidate describes one side of a conflict for a comment line.
+func conflictCandidate(e *poutil.Entry) string {
+	if e == nil {
+		return "<deleted>"
+	}
+	return fmt.Sprintf("%q", e.MsgStr)
+}
+
+// marshalWithConflictMarkers renders the merged file with git style conflict
+// markers around both versions of every conflicting entry.
+func marshalWithConflictMarkers(merged *poutil.File, conflicts []poutil.Conflict) ([]byte, error) {
+	byEntry := make(map[*poutil.Entry]poutil.Conflict, len(conflicts))
+	for _, c := range conflicts {
+		candidate := c.Ours
+		if candidate == nil {
+			candidate = c.Theirs
+		}
+		if entry := merged.GetEntry(candidate.Context, candidate.MsgID); entry != nil {
+			byEntry[entry] = c
+		}
+	}
+
+	headers := &poutil.File{Headers: merged.Headers, Language: merged.Language}
+	data, err := headers.MarshalText()
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	var b strings.Builder
+	b.Write(data)
+	for _, entry := range merged.Entries {
+		c, ok := byEntry[entry]
+		if !ok {
+			b.Write(entry.MarshalText())
+			continue
+		}
+		b.WriteString("<<<<<<< ours\n")
+		if c.Ours != nil {
+			b.Write(c.Ours.MarshalText())
+		}
+		b.WriteString("=======\n")
+		if c.Theirs != nil {
+			b.Write(c.Theirs.MarshalText())
+		}
+		b.WriteString(">>>>>>> theirs\n\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// MergeDriverInstallCmd registers the merge driver for PO files in the
+// current repository (or globally) and in .gitattributes.
+//
+// Usage: dayz-stringtable merge-driver install [--pattern "*.po"] [--attributes .gitattributes] [--global] [--dry-run]
+type MergeDriverInstallCmd struct {
+	Attributes string `short:"a" long:"attributes" description:"Attributes file to add the pattern to" default:".gitattributes"`
+	Pattern    string `short:"p" long:"pattern" description:"Path pattern of PO files" default:"*.po"`
+	Conflict   string `short:"c" long:"conflict" description:"How the driver leaves conflicting entries" default:"markers" choice:"markers" choice:"fuzzy"`
+	Global     bool   `short:"g" long:"global" description:"Write the driver to the global git config"`
+	DryRun     bool   `short:"D" long:"dry-run" description:"Print the stanzas without writing anything"`
+}
+
+// Execute writes the .gitattributes line and the git config driver entries.
+func (cmd *MergeDriverInstallCmd) Execute(_ []string) error {
+	driver := "dayz-stringtable merge-driver"
+	if cmd.Conflict != "markers" {
+		driver += " --conflict " + cmd.Conflict
+	}
+	driver += " %O %A %B"
+
+	attr := fmt.Sprintf("%s merge=%s", cmd.Pattern, mergeDriverName)
+	config := [][2]string{
+		{"merge." + mergeDriverName + ".name", "DayZ stringtable PO merge driver"},
+		{"merge." + mergeDriverName + ".driver", driver},
+	}
+
+	if cmd.DryRun {
+		fmt.Printf("# %s\n%s\n\n", cmd.Attributes, attr)
+		fmt.Printf("# git config\n[merge %q]\n", mergeDriverName)
+		for _, kv := range config {
+			fmt.Printf("\t%s = %s\n", kv[0][strings.LastIndex(kv[0], ".")+1:], kv[1])
+		}
+		return nil
+	}
+
+	if err := appendAttribute(cmd.Attributes, attr); err != nil {
+		return err
+	}
+
+	for _, kv := range config {
+		args := []string{"config"}
+		if cmd.Global {
+			args = append(args, "--global")
+		}
+		if _, err := runGit(append(args, kv[0], kv[1])...); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("merge driver %s installed for %s\n", mergeDriverName, cmd.Pattern)
+	return nil
+}
+
+// appendAttribute adds line to the attributes file unless it is already present.
+func appendAttribute(path, line string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	for _, existing := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(existing) == line {
+			return nil
+		}
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += line + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// writeMergeInputs writes base, ours and theirs PO files with STR_A/STR_B entries.
+func writeMergeInputs(t *testing.T, base, ours, theirs [2]string) (basePath, oursPath, theirsPath string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	write := func(name string, msgstr [2]string) string {
+		po := poutil.NewFile()
+		po.Language = "russian"
+		po.SetHeader("Language", "russian")
+		po.Entries = append(po.Entries,
+			&poutil.Entry{Context: "STR_A", MsgID: "A", MsgStr: msgstr[0]},
+			&poutil.Entry{Context: "STR_B", MsgID: "B", MsgStr: msgstr[1]},
+		)
+		data, err := po.MarshalText()
+		if err != nil {
+			t.Fatalf("failed to marshal PO: %v", err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+
+	return write("base.po", base), write("ours.po", ours), write("theirs.po", theirs)
+}
+
+func TestMergeDriverCmd_Clean(t *testing.T) {
+	basePath, oursPath, theirsPath := writeMergeInputs(t, [2]string{"", ""}, [2]string{"А", ""}, [2]string{"", "Б"})
+
+	cmd := &MergeDriverCmd{Conflict: "markers"}
+	if err := cmd.Execute([]string{basePath, oursPath, theirsPath}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	po, err := poutil.ParseFile(oursPath)
+	if err != nil {
+		t.Fatalf("failed to parse result: %v", err)
+	}
+	if po.GetC("STR_A", "A") != "А" || po.GetC("STR_B", "B") != "Б" {
+		t.Errorf("expected both translations merged, got %+v", po.Entries)
+	}
+	if po.GetHeader("PO-Revision-Date") == "" {
+		t.Error("expected PO-Revision-Date to be updated")
+	}
+}
+
+func TestMergeDriverCmd_ConflictMarkers(t *testing.T) {
+	basePath, oursPath, theirsPath := writeMergeInputs(t, [2]string{"", ""}, [2]string{"А1", ""}, [2]string{"А2", "Б"})
+
+	cmd := &MergeDriverCmd{Conflict: "markers"}
+	if err := cmd.Execute([]string{basePath, oursPath, theirsPath}); err == nil {
+		t.Fatal("expected error for conflicting entries")
+	}
+
+	data, err := os.ReadFile(oursPath)
+	if err != nil {
+		t.Fatalf("failed to read result: %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{"<<<<<<< ours\n", `msgstr "А1"`, "=======\n", `msgstr "А2"`, ">>>>>>> theirs\n", `msgstr "Б"`} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in result:\n%s", want, content)
+		}
+	}
+}
+
+func TestMergeDriverCmd_ConflictFuzzy(t *testing.T) {
+	basePath, oursPath, theirsPath := writeMergeInputs(t, [2]string{"", ""}, [2]string{"А1", ""}, [2]string{"А2", ""})
+
+	cmd := &MergeDriverCmd{Conflict: "fuzzy"}
+	if err := cmd.Execute([]string{basePath, oursPath, theirsPath}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	po, err := poutil.ParseFile(oursPath)
+	if err != nil {
+		t.Fatalf("failed to parse result: %v", err)
+	}
+	entry := po.GetEntry("STR_A", "A")
+	if entry == nil || !entry.HasFlag("fuzzy") {
+		t.Fatalf("expected fuzzy entry, got %+v", entry)
+	}
+	comments := strings.Join(entry.Comments, "\n")
+	if !strings.Contains(comments, `# merge ours: "А1"`) || !strings.Contains(comments, `# merge theirs: "А2"`) {
+		t.Errorf("expected both candidates in comments, got:\n%s", comments)
+	}
+}
+
+func TestAppendAttribute(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gitattributes")
+	if err := os.WriteFile(path, []byte("*.csv text"), 0o644); err != nil {
+		t.Fatalf("failed to write attributes: %v", err)
+	}
+
+	for range 2 {
+		if err := appendAttribute(path, "*.po merge=dayz-stringtable"); err != nil {
+			t.Fatalf("appendAttribute failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read attributes: %v", err)
+	}
+	if got, want := string(data), "*.csv text\n*.po merge=dayz-stringtable\n"; got != want {
+		t.Errorf("attributes = %q, want %q", got, want)
+	}
+}
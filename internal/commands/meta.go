@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+	"github.com/woozymasta/dayz-stringtable/internal/qa"
+)
+
+// Default names of CSV metadata columns ignored by the game.
+const (
+	DefaultCommentColumn = "comment"
+	DefaultMaxLenColumn  = "maxlen"
+)
+
+// metaColumns holds CSV column indices of translator metadata (-1 if absent).
+type metaColumns struct {
+	comment int
+	maxLen  int
+}
+
+// findMetaColumns locates metadata columns by name in the CSV header.
+func findMetaColumns(header []string, commentColumn, maxLenColumn string) metaColumns {
+	meta := metaColumns{comment: -1, maxLen: -1}
+	for i, name := range header {
+		switch {
+		case commentColumn != "" && name == commentColumn:
+			meta.comment = i
+		case maxLenColumn != "" && name == maxLenColumn:
+			meta.maxLen = i
+		}
+	}
+	return meta
+}
+
+// isMetaColumn reports whether a CSV column name is a metadata column.
+func isMetaColumn(name, commentColumn, maxLenColumn string) bool {
+	return name != "" && (name == commentColumn || name == maxLenColumn)
+}
+
+// apply turns the metadata of a CSV row into "#." extracted comments and
+// a max-length flag on the entry. Metadata absent from the CSV header is
+// left untouched, so hand-written comments survive in projects without it.
+func (m metaColumns) apply(entry *poutil.Entry, row []string) {
+	if entry == nil {
+		return
+	}
+
+	if m.comment >= 0 {
+		var lines []string
+		if m.comment < len(row) && strings.TrimSpace(row[m.comment]) != "" {
+			lines = strings.Split(strings.TrimSpace(row[m.comment]), "\n")
+		}
+		entry.SetExtractedComments(lines)
+	}
+
+	if m.maxLen >= 0 {
+		entry.RemoveFlagValue(qa.MaxLengthFlag)
+		if m.maxLen < len(row) {
+			if n, err := strconv.Atoi(strings.TrimSpace(row[m.maxLen])); err == nil && n > 0 {
+				entry.AddFlag(qa.MaxLengthFlag + ":" + strconv.Itoa(n))
+			}
+		}
+	}
+}
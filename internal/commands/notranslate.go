@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// Kinds of do-not-translate rules.
+const (
+	ruleKey   = "key"   // Regex matched against msgctxt (the CSV key)
+	ruleMsgID = "msgid" // Regex matched against msgid (the original text)
+	ruleTerm  = "term"  // Exact original text, surrounding whitespace ignored
+)
+
+// noTranslateRule is a single line of a rule file.
+type noTranslateRule struct {
+	pattern *regexp.Regexp
+	Kind    string
+	Value   string
+	Source  string // file:line the rule was read from
+}
+
+// String describes the rule for reports.
+func (r *noTranslateRule) String() string {
+	return fmt.Sprintf("%s %q (%s)", r.Kind, r.Value, r.Source)
+}
+
+// match reports whether the rule matches the key/original pair.
+func (r *noTranslateRule) match(key, original string) bool {
+	switch r.Kind {
+	case ruleKey:
+		return r.pattern.MatchString(key)
+	case ruleMsgID:
+		return r.pattern.MatchString(original)
+	default:
+		return strings.TrimSpace(original) == r.Value
+	}
+}
+
+// noTranslateRules marks entries notranslate by key or original text and
+// collects the entries marked, for the report.
+type noTranslateRules struct {
+	rules   []*noTranslateRule
+	matched map[string]*noTranslateRule // keys marked by a rule
+}
+
+// loadNoTranslateRules reads a rule file with one "kind: value" rule per
+// line, where kind is key, msgid or term. Empty lines and lines starting
+// with # are skipped. An empty path returns nil rules, which match nothing.
+func loadNoTranslateRules(path string) (*noTranslateRules, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rules: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	r := &noTranslateRules{matched: make(map[string]*noTranslateRule)}
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kind, value, ok := strings.Cut(line, ":")
+		kind, value = strings.ToLower(strings.TrimSpace(kind)), strings.TrimSpace(value)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%s:%d: expected 'kind: value'", path, lineNum)
+		}
+
+		rule := &noTranslateRule{Kind: kind, Value: value, Source: fmt.Sprintf("%s:%d", path, lineNum)}
+		switch kind {
+		case ruleKey, ruleMsgID:
+			if rule.pattern, err = regexp.Compile(value); err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid regex: %w", path, lineNum, err)
+			}
+		case ruleTerm:
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown rule kind '%s', expected key, msgid or term", path, lineNum, kind)
+		}
+		r.rules = append(r.rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+	return r, nil
+}
+
+// match returns the first rule matching the key/original pair.
+func (r *noTranslateRules) match(key, original string) *noTranslateRule {
+	if r == nil {
+		return nil
+	}
+	for _, rule := range r.rules {
+		if rule.match(key, original) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// apply adds the "# notranslate" comment to an entry matched by a rule,
+// as clean does, and reports whether the entry was changed.
+// Entries already marked are left as is.
+func (r *noTranslateRules) apply(entry *poutil.Entry) bool {
+	if r == nil || entry.HasNoTranslate() {
+		return false
+	}
+	rule := r.match(entry.Context, entry.MsgID)
+	if rule == nil {
+		return false
+	}
+	entry.Comments = append([]string{"# notranslate"}, entry.Comments...)
+	if _, ok := r.matched[entry.Context]; !ok {
+		r.matched[entry.Context] = rule
+	}
+	return true
+}
+
+// printReport lists keys marked notranslate with the rule that matched,
+// once per key for all languages.
+func (r *noTranslateRules) printReport(w io.Writer) {
+	if r == nil || len(r.matched) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(r.matched))
+	for key := range r.matched {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(w, "notranslate %s: %s\n", key, r.matched[key])
+	}
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+func TestLoadNoTranslateRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notranslate.rules")
+	content := `# Class names and links
+key: ^STR_Class_
+msgid: ^https?://
+msgid: ^([\s/:.-]|%[1-9sd]|\{\w+\})*$
+
+term: DayZ
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write rules: %v", err)
+	}
+
+	rules, err := loadNoTranslateRules(path)
+	if err != nil {
+		t.Fatalf("loadNoTranslateRules failed: %v", err)
+	}
+
+	tests := []struct {
+		key, original string
+		want          string // expected rule source, empty if none
+	}{
+		{"STR_Class_Apple", "Apple", path + ":2"},
+		{"STR_Link", "https://example.com", path + ":3"},
+		{"STR_Count", "%1 / %2", path + ":4"},
+		{"STR_Game", " DayZ ", path + ":6"},
+		{"STR_Title", "DayZ Standalone", ""},
+		{"STR_Exit", "Exit", ""},
+	}
+	for _, tt := range tests {
+		rule := rules.match(tt.key, tt.original)
+		got := ""
+		if rule != nil {
+			got = rule.Source
+		}
+		if got != tt.want {
+			t.Errorf("match(%q, %q) = %q, want %q", tt.key, tt.original, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"key ^STR_", "msgid: (", "regex: .*", "term:"} {
+		if err := os.WriteFile(path, []byte(bad+"\n"), 0o600); err != nil {
+			t.Fatalf("failed to write rules: %v", err)
+		}
+		if _, err := loadNoTranslateRules(path); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestPosCmdNoTranslateRules(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "stringtable.csv")
+	csv := `"Language","original","russian"
+"STR_Class_Apple","Apple",""
+"STR_Exit","Exit","Выход"
+`
+	if err := os.WriteFile(csvPath, []byte(csv), 0o600); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+	rulesPath := filepath.Join(dir, "notranslate.rules")
+	if err := os.WriteFile(rulesPath, []byte("key: ^STR_Class_\n"), 0o600); err != nil {
+		t.Fatalf("failed to write rules: %v", err)
+	}
+
+	poDir := filepath.Join(dir, "l18n")
+	cmd := PosCmd{Input: csvPath, Langs: "russian", OutDir: poDir, Rules: rulesPath}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("PosCmd.Execute failed: %v", err)
+	}
+
+	po, err := poutil.ParseFile(filepath.Join(poDir, "russian.po"))
+	if err != nil {
+		t.Fatalf("failed to parse PO file: %v", err)
+	}
+	if entry := po.GetEntry("STR_Class_Apple", "Apple"); entry == nil || !entry.HasNoTranslate() {
+		t.Errorf("STR_Class_Apple should be marked notranslate")
+	}
+	if entry := po.GetEntry("STR_Exit", "Exit"); entry == nil || entry.HasNoTranslate() {
+		t.Errorf("STR_Exit should not be marked notranslate")
+	}
+
+	// Running again over the marked file must not add a second comment
+	clean := CleanCmd{PoDir: poDir, Rules: rulesPath}
+	if err := clean.Execute(nil); err != nil {
+		t.Fatalf("CleanCmd.Execute failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(poDir, "russian.po"))
+	if err != nil {
+		t.Fatalf("failed to read PO file: %v", err)
+	}
+	if n := strings.Count(string(data), "# notranslate"); n != 1 {
+		t.Errorf("got %d notranslate comments, want 1:\n%s", n, data)
+	}
+}
@@ -11,13 +11,18 @@ import (
 
 // PosCmd generates PO files for each language from a CSV file.
 //
-// Usage: dayz-stringtable pos --input stringtable.csv --langs en,de,ru --outdir po/ [--force] [--project-version VERSION]
+// Usage: dayz-stringtable pos --input stringtable.csv --langs en,de,ru --outdir po/ [--force] [--project-version VERSION] [--source DIR] [--rules notranslate.rules]
 type PosCmd struct {
-	Input          string `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
-	OutDir         string `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
-	Langs          string `short:"l" long:"langs" description:"Comma-sep list of langs (default all)"`
-	ProjectVersion string `short:"P" long:"project-version" description:"Set Project-Id-Version header (project name and version)"`
-	Force          bool   `short:"f" long:"force" description:"Overwrite existing files"`
+	Input          string   `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
+	OutDir         string   `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
+	Langs          string   `short:"l" long:"langs" description:"Comma-sep list of langs (default all)"`
+	ProjectVersion string   `short:"P" long:"project-version" description:"Set Project-Id-Version header (project name and version)"`
+	SourceDir      string   `short:"s" long:"source" description:"Mod source directory to scan for #: references"`
+	Prefixes       []string `long:"prefix" description:"Key prefixes recognized in sources (repeatable, default STR_)"`
+	CommentColumn  string   `long:"comment-column" description:"CSV column with translator notes, emitted as #. comments" default:"comment"`
+	MaxLenColumn   string   `long:"maxlen-column" description:"CSV column with max translation length, emitted as max-length flag" default:"maxlen"`
+	Rules          string   `long:"rules" description:"Do-not-translate rule file, matching entries are marked notranslate"`
+	Force          bool     `short:"f" long:"force" description:"Overwrite existing files"`
 }
 
 // Execute reads CSV and generates PO files for each specified language.
@@ -33,11 +38,22 @@ func (cmd *PosCmd) Execute(_ []string) error {
 
 	langs := ParseLanguages(cmd.Langs)
 
+	rules, err := loadNoTranslateRules(cmd.Rules)
+	if err != nil {
+		return err
+	}
+
+	refs, err := loadSourceRefs(cmd.SourceDir, rows, cmd.Prefixes)
+	if err != nil {
+		return err
+	}
+
 	// Map headers to column indices
 	headers := make(map[string]int)
 	for i, h := range rows[0] {
 		headers[h] = i
 	}
+	meta := findMetaColumns(rows[0], cmd.CommentColumn, cmd.MaxLenColumn)
 
 	for _, lang := range langs {
 		po := poutil.NewFile()
@@ -55,7 +71,11 @@ func (cmd *PosCmd) Execute(_ []string) error {
 				msg = row[idx]
 			}
 			po.SetC(row[0], row[1], msg)
+			entry := po.GetEntry(row[0], row[1])
+			meta.apply(entry, row)
+			rules.apply(entry)
 		}
+		applySourceRefs(po, refs)
 
 		// Update build headers after all entries are added
 		po.UpdateBuildHeaders(cmd.ProjectVersion)
@@ -78,5 +98,11 @@ func (cmd *PosCmd) Execute(_ []string) error {
 		}
 	}
 
+	// Keep the report out of PO data written to stdout
+	if cmd.OutDir == "" {
+		rules.printReport(os.Stderr)
+	} else {
+		rules.printReport(os.Stdout)
+	}
 	return nil
 }
@@ -11,12 +11,17 @@ import (
 
 // PotCmd generates a POT template file from a CSV file.
 //
-// Usage: dayz-stringtable pot --input stringtable.csv --output template.pot [--force] [--project-version VERSION]
+// Usage: dayz-stringtable pot --input stringtable.csv --output template.pot [--force] [--project-version VERSION] [--source DIR] [--check]
 type PotCmd struct {
-	Input          string `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
-	Output         string `short:"o" long:"output" description:"POT output file (stdout if empty)"`
-	ProjectVersion string `short:"P" long:"project-version" description:"Set Project-Id-Version header (project name and version)"`
-	Force          bool   `short:"f" long:"force" description:"Overwrite existing file"`
+	Input          string   `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
+	Output         string   `short:"o" long:"output" description:"POT output file (stdout if empty)"`
+	ProjectVersion string   `short:"P" long:"project-version" description:"Set Project-Id-Version header (project name and version)"`
+	SourceDir      string   `short:"s" long:"source" description:"Mod source directory to scan for #: references"`
+	Prefixes       []string `long:"prefix" description:"Key prefixes recognized in sources (repeatable, default STR_)"`
+	CommentColumn  string   `long:"comment-column" description:"CSV column with translator notes, emitted as #. comments" default:"comment"`
+	MaxLenColumn   string   `long:"maxlen-column" description:"CSV column with max translation length, emitted as max-length flag" default:"maxlen"`
+	Force          bool     `short:"f" long:"force" description:"Overwrite existing file"`
+	Check          bool     `short:"c" long:"check" description:"Don't write, exit with error if --output is not up to date"`
 }
 
 // Execute reads CSV and generates a POT template with all original strings.
@@ -57,6 +62,8 @@ func (cmd *PotCmd) Execute(_ []string) error {
 		}
 	}
 
+	meta := findMetaColumns(rows[0], cmd.CommentColumn, cmd.MaxLenColumn)
+
 	// CSV format: row[0] = key, row[1] = original text
 	// PO format: msgctxt = key, msgid = original text
 	for _, row := range rows[1:] {
@@ -64,7 +71,14 @@ func (cmd *PotCmd) Execute(_ []string) error {
 			continue
 		}
 		po.SetC(row[0], row[1], "")
+		meta.apply(po.GetEntry(row[0], row[1]), row)
+	}
+
+	refs, err := loadSourceRefs(cmd.SourceDir, rows, cmd.Prefixes)
+	if err != nil {
+		return err
 	}
+	applySourceRefs(po, refs)
 
 	// Check if CSV hash has changed
 	csvHashChanged := true
@@ -78,6 +92,9 @@ func (cmd *PotCmd) Execute(_ []string) error {
 		}
 	}
 
+	// Save CSV hash in header, before the content hash is computed from headers
+	po.SetHeader("X-CSV-Hash", fmt.Sprintf("%016x", csvHash))
+
 	// Update build headers after all entries are added
 	po.UpdateBuildHeaders(cmd.ProjectVersion)
 
@@ -87,14 +104,26 @@ func (cmd *PotCmd) Execute(_ []string) error {
 		po.SetHeader("POT-Creation-Date", now)
 	}
 
-	// Save CSV hash in header
-	po.SetHeader("X-CSV-Hash", fmt.Sprintf("%016x", csvHash))
+	// The tool version used to generate the template doesn't make it
+	// outdated, nor does a content hash written by an earlier version
+	if cmd.Check && existingPOT != nil {
+		if generator := existingPOT.GetHeader("X-Generator"); generator != "" {
+			po.SetHeader("X-Generator", generator)
+		}
+		if hash := existingPOT.GetHeader("X-Content-Hash"); po.MatchesContentHash(hash) {
+			po.SetHeader("X-Content-Hash", hash)
+		}
+	}
 
 	data, err := po.MarshalText()
 	if err != nil {
 		return fmt.Errorf("failed to marshal POT: %w", err)
 	}
 
+	if cmd.Check {
+		return checkUpToDate(cmd.Output, data, "pot")
+	}
+
 	if err := csvutil.WriteFile(cmd.Output, data, cmd.Force); err != nil {
 		return fmt.Errorf("failed to write output: %w", err)
 	}
@@ -5,6 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+	"github.com/woozymasta/dayz-stringtable/internal/vars"
 )
 
 // TestPotCmd verifies that PotCmd generates a .pot file from CSV.
@@ -48,3 +51,128 @@ func TestPotCmd(t *testing.T) {
 		}
 	}
 }
+
+// TestPotCmd_MetadataColumns verifies that comment and maxlen columns become
+// extracted comments and max-length flags.
+func TestPotCmd_MetadataColumns(t *testing.T) {
+	tmpDir := t.TempDir()
+	csvContent := `"Language","original","comment","maxlen",
+"STR_Title","Title","Menu title",24,
+"STR_Body","Body","","",
+`
+	csvPath := filepath.Join(tmpDir, "stringtable.csv")
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+	outPath := filepath.Join(tmpDir, "template.pot")
+
+	cmd := &PotCmd{
+		Input:         csvPath,
+		Output:        outPath,
+		CommentColumn: DefaultCommentColumn,
+		MaxLenColumn:  DefaultMaxLenColumn,
+	}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("PotCmd.Execute failed: %v", err)
+	}
+
+	pot, err := poutil.ParseFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to parse POT: %v", err)
+	}
+
+	title := pot.GetEntry("STR_Title", "Title")
+	if title == nil {
+		t.Fatal("STR_Title not found")
+	}
+	if got := title.ExtractedComments(); len(got) != 1 || got[0] != "Menu title" {
+		t.Errorf("STR_Title extracted comments = %v", got)
+	}
+	if got, ok := title.FlagValue("max-length"); !ok || got != "24" {
+		t.Errorf("STR_Title max-length = %q, %v", got, ok)
+	}
+
+	body := pot.GetEntry("STR_Body", "Body")
+	if body == nil {
+		t.Fatal("STR_Body not found")
+	}
+	if len(body.Comments) != 0 {
+		t.Errorf("STR_Body comments = %v, want none", body.Comments)
+	}
+}
+
+// TestPotCmd_Check verifies that an unchanged template is reproduced byte for
+// byte and that --check detects a template outdated by CSV changes.
+func TestPotCmd_Check(t *testing.T) {
+	tmpDir := t.TempDir()
+	csvPath := filepath.Join(tmpDir, "stringtable.csv")
+	if err := os.WriteFile(csvPath, []byte("\"Language\",\"original\",\n\"STR_Yes\",\"Yes\",\n"), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+	outPath := filepath.Join(tmpDir, "template.pot")
+
+	cmd := &PotCmd{Input: csvPath, Output: outPath, Force: true}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("PotCmd.Execute failed: %v", err)
+	}
+	first, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read POT file: %v", err)
+	}
+
+	check := &PotCmd{Input: csvPath, Output: outPath, Check: true}
+	if err := check.Execute(nil); err != nil {
+		t.Fatalf("expected up to date template, got: %v", err)
+	}
+
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("PotCmd.Execute failed: %v", err)
+	}
+	second, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read POT file: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Errorf("expected identical output for unchanged CSV:\n%s\n---\n%s", first, second)
+	}
+
+	if err := os.WriteFile(csvPath, []byte("\"Language\",\"original\",\n\"STR_Yes\",\"Yes\",\n\"STR_No\",\"No\",\n"), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+	if err := check.Execute(nil); err == nil {
+		t.Fatal("expected error for outdated template")
+	}
+	after, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read POT file: %v", err)
+	}
+	if !bytes.Equal(first, after) {
+		t.Error("--check must not rewrite the template")
+	}
+}
+
+// TestPotCmd_CheckToolUpgrade verifies that a template written by another
+// version of the tool is up to date for --check.
+func TestPotCmd_CheckToolUpgrade(t *testing.T) {
+	tmpDir := t.TempDir()
+	csvPath := filepath.Join(tmpDir, "stringtable.csv")
+	if err := os.WriteFile(csvPath, []byte("\"Language\",\"original\",\n\"STR_Yes\",\"Yes\",\n"), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+	outPath := filepath.Join(tmpDir, "template.pot")
+
+	version := vars.Version
+	t.Cleanup(func() { vars.Version = version })
+
+	vars.Version = "1.0.0"
+	cmd := &PotCmd{Input: csvPath, Output: outPath, Force: true}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("PotCmd.Execute failed: %v", err)
+	}
+
+	vars.Version = "1.1.0"
+	check := &PotCmd{Input: csvPath, Output: outPath, Check: true}
+	if err := check.Execute(nil); err != nil {
+		t.Fatalf("expected template of 1.0.0 up to date for 1.1.0, got: %v", err)
+	}
+}
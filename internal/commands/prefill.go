@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// PrefillCmd fills untranslated entries with the translation of an entry
+// with the same original text, from the same PO file or PO files of
+// other directories. Prefilled entries are marked fuzzy for review.
+//
+// Usage: dayz-stringtable prefill --podir l18n [--from ../OtherMod/l18n] [--lang russian] [--dry-run]
+type PrefillCmd struct {
+	PoDir  string   `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
+	From   []string `short:"s" long:"from" description:"Other PO directories to take translations from (repeatable)"`
+	Langs  []string `short:"l" long:"lang" description:"Filter by languages (comma-separated or repeatable)"`
+	DryRun bool     `short:"n" long:"dry-run" description:"Only print counts, don't write PO files"`
+}
+
+// prefillSource is a translated entry that untranslated entries with the
+// same msgid are filled from.
+type prefillSource struct {
+	entry *poutil.Entry
+	path  string // PO file of other directories, empty for the same file
+}
+
+// Execute prefills every PO file of the directory and prints counts per language.
+func (cmd *PrefillCmd) Execute(_ []string) error {
+	files, err := filepath.Glob(filepath.Join(cmd.PoDir, "*.po"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no PO files found in %s", cmd.PoDir)
+	}
+
+	perLang := make(map[string]int)
+	for _, path := range files {
+		lang := ExtractLanguageName(path)
+		if len(cmd.Langs) > 0 && !ContainsLanguage(cmd.Langs, lang) {
+			continue
+		}
+
+		filled, err := cmd.prefillPOFile(path, lang)
+		if err != nil {
+			return fmt.Errorf("prefill %s: %w", path, err)
+		}
+		perLang[lang] = filled
+	}
+
+	total := 0
+	for _, lang := range orderLangs(perLang) {
+		total += perLang[lang]
+		fmt.Printf("lang %s: %d prefilled\n", lang, perLang[lang])
+	}
+	if cmd.DryRun && total > 0 {
+		fmt.Println("dry run, PO files not written")
+	}
+	return nil
+}
+
+// prefillPOFile fills untranslated entries of a PO file and writes it
+// back if anything changed. Returns the number of prefilled entries.
+func (cmd *PrefillCmd) prefillPOFile(path, lang string) (int, error) {
+	po, err := poutil.ParseFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse PO file: %w", err)
+	}
+
+	// Translations of the same file take precedence over other directories
+	sources := make(map[string]prefillSource)
+	addPrefillSources(sources, po, "")
+	for _, dir := range cmd.From {
+		other := filepath.Join(dir, lang+".po")
+		if _, err := os.Stat(other); os.IsNotExist(err) {
+			continue
+		}
+		otherPo, err := poutil.ParseFile(other)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %s: %w", other, err)
+		}
+		addPrefillSources(sources, otherPo, other)
+	}
+
+	filled := 0
+	for _, entry := range po.Entries {
+		if entry.MsgStr != "" || entry.MsgID == "" || entry.HasNoTranslate() {
+			continue
+		}
+		src, ok := sources[entry.MsgID]
+		if !ok {
+			continue
+		}
+
+		comment := "# prefilled from " + src.entry.Context
+		if src.path != "" {
+			comment += " in " + filepath.ToSlash(src.path)
+		}
+		entry.MsgStr = src.entry.MsgStr
+		entry.AddFlag("fuzzy")
+		entry.Comments = append([]string{comment}, entry.Comments...)
+		filled++
+	}
+
+	if filled == 0 || cmd.DryRun {
+		return filled, nil
+	}
+
+	po.UpdateBuildHeaders("")
+	data, err := po.MarshalText()
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal PO file: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return 0, err
+	}
+	return filled, nil
+}
+
+// addPrefillSources indexes reviewed translations of a PO file by msgid.
+// Fuzzy and notranslate entries are skipped, the first translation of a
+// msgid wins.
+func addPrefillSources(sources map[string]prefillSource, po *poutil.File, path string) {
+	for _, entry := range po.Entries {
+		if entry.MsgStr == "" || strings.TrimSpace(entry.MsgID) == "" || entry.HasNoTranslate() || entry.HasFlag("fuzzy") {
+			continue
+		}
+		if _, ok := sources[entry.MsgID]; !ok {
+			sources[entry.MsgID] = prefillSource{entry: entry, path: path}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+func TestPrefillCmd(t *testing.T) {
+	poDir := writeCheckFixture(t,
+		&poutil.Entry{Context: "STR_Close", MsgID: "Close", MsgStr: "Закрыть"},
+		&poutil.Entry{Context: "STR_Menu_Close", MsgID: "Close"},
+		&poutil.Entry{Context: "STR_Cancel", MsgID: "Cancel"},
+		&poutil.Entry{Context: "STR_Guessed", MsgID: "Open", MsgStr: "Открыто", Comments: []string{"#, fuzzy"}},
+		&poutil.Entry{Context: "STR_Open", MsgID: "Open"},
+		&poutil.Entry{Context: "STR_Space", MsgID: "Not enough space"},
+	)
+	otherDir := writeCheckFixture(t,
+		&poutil.Entry{Context: "STR_Other_Cancel", MsgID: "Cancel", MsgStr: "Отмена"},
+		&poutil.Entry{Context: "STR_Other_Close", MsgID: "Close", MsgStr: "Закрыть окно"},
+	)
+	path := filepath.Join(poDir, "russian.po")
+
+	cmd := &PrefillCmd{PoDir: poDir, From: []string{otherDir}, DryRun: true}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	po, err := poutil.ParseFile(path)
+	if err != nil {
+		t.Fatalf("failed to parse PO: %v", err)
+	}
+	if po.GetC("STR_Menu_Close", "Close") != "" {
+		t.Fatal("dry run wrote the PO file")
+	}
+
+	cmd.DryRun = false
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("PrefillCmd.Execute failed: %v", err)
+	}
+	if po, err = poutil.ParseFile(path); err != nil {
+		t.Fatalf("failed to parse PO: %v", err)
+	}
+
+	entry := po.GetEntry("STR_Menu_Close", "Close")
+	if entry.MsgStr != "Закрыть" || !entry.HasFlag("fuzzy") {
+		t.Errorf("same file translation not prefilled: %+v", entry)
+	}
+	if !strings.Contains(strings.Join(entry.Comments, "\n"), "# prefilled from STR_Close") {
+		t.Errorf("missing source comment: %v", entry.Comments)
+	}
+	entry = po.GetEntry("STR_Cancel", "Cancel")
+	if entry.MsgStr != "Отмена" || !strings.Contains(strings.Join(entry.Comments, "\n"), "STR_Other_Cancel in ") {
+		t.Errorf("other directory translation not prefilled: %+v", entry)
+	}
+	if po.GetC("STR_Open", "Open") != "" {
+		t.Error("prefilled from a fuzzy entry")
+	}
+	if po.GetC("STR_Space", "Not enough space") != "" {
+		t.Error("prefilled without a source")
+	}
+
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read PO: %v", err)
+	}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("second run failed: %v", err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read PO: %v", err)
+	}
+	if string(before) != string(after) {
+		t.Error("second run changed the PO file")
+	}
+}
@@ -0,0 +1,100 @@
+package commands
+
+//lint:file-ignore SA5008 go-flags requires duplicate choice tags on struct fields
+
+import (
+	"fmt"
+
+	"github.com/woozymasta/dayz-stringtable/internal/csvutil"
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+	"github.com/woozymasta/dayz-stringtable/internal/pseudo"
+)
+
+// PseudoCmd generates a pseudo-localized PO file or CSV column from the
+// original texts of a CSV file.
+//
+// Usage: dayz-stringtable pseudo --input stringtable.csv [--output l18n/english.po] [--format po|csv] [--lang english] [--transform accents] [--expansion 30] [--force]
+type PseudoCmd struct {
+	Input      string   `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
+	Output     string   `short:"o" long:"output" description:"PO or CSV output (stdout if empty)"`
+	Format     string   `short:"F" long:"format" description:"Write a PO file, or the input CSV with the language column replaced" default:"po" choice:"po" choice:"csv"`
+	Lang       string   `short:"l" long:"lang" description:"Language of the PO file or CSV column" default:"english"`
+	Transforms []string `short:"t" long:"transform" description:"Transforms to apply (repeatable)" default:"accents" default:"expand" default:"brackets" choice:"accents" choice:"expand" choice:"brackets" choice:"mirror" choice:"rtl"`
+	Expansion  int      `short:"e" long:"expansion" description:"Text expansion of the expand transform in percent" default:"30"`
+	Force      bool     `short:"f" long:"force" description:"Overwrite existing files"`
+}
+
+// Execute reads the CSV and writes pseudo-localized originals.
+func (cmd *PseudoCmd) Execute(_ []string) error {
+	opts, err := pseudo.ParseOptions(cmd.Transforms, cmd.Expansion)
+	if err != nil {
+		return err
+	}
+
+	rows, err := csvutil.LoadCSV(cmd.Input)
+	if err != nil {
+		return fmt.Errorf("failed to load CSV: %w", err)
+	}
+	if len(rows) < 2 {
+		return fmt.Errorf("CSV must have header and at least one data row")
+	}
+
+	var data []byte
+	if cmd.Format == "csv" {
+		data = marshalCSVRows(pseudoColumn(rows, cmd.Lang, opts), "\n")
+	} else {
+		po := poutil.NewFile()
+		po.Language = cmd.Lang
+		po.SetHeader("Language", cmd.Lang)
+		// CSV format: row[0] = key, row[1] = original text
+		for _, row := range rows[1:] {
+			if len(row) < 2 {
+				continue
+			}
+			po.SetC(row[0], row[1], opts.Apply(row[1]))
+		}
+		po.UpdateBuildHeaders("")
+		if data, err = po.MarshalText(); err != nil {
+			return fmt.Errorf("failed to marshal PO file: %w", err)
+		}
+	}
+
+	if err := csvutil.WriteFile(cmd.Output, data, cmd.Force); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+// pseudoColumn returns a copy of CSV rows with the column of lang set to
+// pseudo-localized originals, the column is appended if missing.
+func pseudoColumn(rows [][]string, lang string, opts pseudo.Options) [][]string {
+	col := -1
+	for i, h := range rows[0] {
+		if h == lang {
+			col = i
+			break
+		}
+	}
+	header := append([]string(nil), rows[0]...)
+	if col < 0 {
+		// Replace the empty column left by a trailing comma
+		if n := len(header); n > 0 && header[n-1] == "" {
+			header = header[:n-1]
+		}
+		col = len(header)
+		header = append(header, lang)
+	}
+
+	out := [][]string{header}
+	for _, row := range rows[1:] {
+		rec := append([]string(nil), row...)
+		for len(rec) <= col {
+			rec = append(rec, "")
+		}
+		if len(row) >= 2 {
+			rec[col] = opts.Apply(row[1])
+		}
+		out = append(out, rec)
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPseudoCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	csvPath := filepath.Join(tmpDir, "input.csv")
+	csvContent := `"Language","original","russian",
+"STR_Ammo","Ammo: %1","Патроны: %1",
+`
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	poPath := filepath.Join(tmpDir, "english.po")
+	cmd := &PseudoCmd{Input: csvPath, Output: poPath, Format: "po", Lang: "english", Transforms: []string{"accents", "brackets"}}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("PseudoCmd.Execute failed: %v", err)
+	}
+	data, err := os.ReadFile(poPath)
+	if err != nil {
+		t.Fatalf("failed to read PO: %v", err)
+	}
+	if !strings.Contains(string(data), `msgstr "[Åmmö: %1]"`) {
+		t.Errorf("pseudo translation missing from PO:\n%s", data)
+	}
+
+	csvOut := filepath.Join(tmpDir, "pseudo.csv")
+	cmd = &PseudoCmd{Input: csvPath, Output: csvOut, Format: "csv", Lang: "russian", Transforms: []string{"brackets"}}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("PseudoCmd.Execute failed: %v", err)
+	}
+	data, err = os.ReadFile(csvOut)
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	want := `"Language","original","russian",
+"STR_Ammo","Ammo: %1","[Ammo: %1]",
+`
+	if string(data) != want {
+		t.Errorf("CSV = %q, want %q", data, want)
+	}
+}
+
+func TestMakeCmdPseudo(t *testing.T) {
+	tmpDir := t.TempDir()
+	csvPath := filepath.Join(tmpDir, "input.csv")
+	if err := os.WriteFile(csvPath, []byte(`"Language","original"
+"STR_Save","Save"
+`), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	outputPath := filepath.Join(tmpDir, "full.csv")
+	cmd := MakeCmd{Input: csvPath, PoDir: filepath.Join(tmpDir, "po"), Output: outputPath, Pseudo: "english", PseudoTransforms: []string{"accents"}}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("MakeCmd.Execute failed: %v", err)
+	}
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(data), `"Language","original","english",`) || !strings.Contains(string(data), `"STR_Save","Save","Šåvé",`) {
+		t.Errorf("pseudo column missing from output:\n%s", data)
+	}
+}
@@ -0,0 +1,249 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/woozymasta/dayz-stringtable/internal/csvutil"
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+	"github.com/woozymasta/dayz-stringtable/internal/srcscan"
+)
+
+// RenameCmd renames a key in the CSV, every PO file, the POT template,
+// and optionally in "#KEY"/"$KEY" references of a source tree.
+//
+// Usage: dayz-stringtable rename --input stringtable.csv --podir l18n [--pot stringtable.pot] [--source ./scripts] [--dry-run] OLD NEW
+type RenameCmd struct {
+	Args struct {
+		Old string `positional-arg-name:"OLD" description:"Existing key"`
+		New string `positional-arg-name:"NEW" description:"New key"`
+	} `positional-args:"yes" required:"yes"`
+	Input     string `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
+	PoDir     string `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
+	Template  string `short:"t" long:"pot" description:"POT template to update (skipped if empty or missing)"`
+	SourceDir string `short:"s" long:"source" description:"Source tree to rewrite #KEY and $KEY references in"`
+	DryRun    bool   `short:"D" long:"dry-run" description:"Show what would change without writing files"`
+}
+
+// Execute renames the key everywhere it is defined.
+func (cmd *RenameCmd) Execute(_ []string) error {
+	oldKey, newKey := cmd.Args.Old, cmd.Args.New
+	if oldKey == "" || newKey == "" {
+		return fmt.Errorf("both OLD and NEW keys are required")
+	}
+	if oldKey == newKey {
+		return fmt.Errorf("OLD and NEW keys are the same")
+	}
+
+	rows, err := csvutil.LoadCSV(cmd.Input)
+	if err != nil {
+		return fmt.Errorf("failed to load CSV: %w", err)
+	}
+
+	rowIdx := findKeyRow(rows, oldKey)
+	if rowIdx < 0 {
+		return fmt.Errorf("key '%s' not found in %s", oldKey, cmd.Input)
+	}
+	if findKeyRow(rows, newKey) >= 0 {
+		return fmt.Errorf("key '%s' already exists in %s", newKey, cmd.Input)
+	}
+
+	rows[rowIdx][0] = newKey
+	fmt.Printf("csv %s: rename %s -> %s (row %d)\n", cmd.Input, oldKey, newKey, rowIdx+1)
+	if err := writeEditedCSV(cmd.Input, rows, cmd.DryRun); err != nil {
+		return err
+	}
+
+	rename := func(po *poutil.File) int {
+		count := 0
+		for _, entry := range po.Entries {
+			if entry.Context == oldKey {
+				entry.Context = newKey
+				count++
+			}
+		}
+		return count
+	}
+	if err := editKeyFiles(cmd.Input, cmd.PoDir, cmd.Template, rename, cmd.DryRun); err != nil {
+		return err
+	}
+
+	if cmd.SourceDir == "" {
+		return nil
+	}
+
+	return srcscan.WalkSources(cmd.SourceDir, func(path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		content, count := srcscan.ReplaceKeyRefs(string(data), oldKey, newKey)
+		if count == 0 {
+			return nil
+		}
+		fmt.Printf("src %s: %d references\n", path, count)
+		if cmd.DryRun {
+			return nil
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// RemoveCmd deletes a key from the CSV, every PO file and the POT template.
+// Remaining "#KEY"/"$KEY" references in a source tree are only reported.
+//
+// Usage: dayz-stringtable rm --input stringtable.csv --podir l18n [--pot stringtable.pot] [--source ./scripts] [--dry-run] KEY
+type RemoveCmd struct {
+	Args struct {
+		Key string `positional-arg-name:"KEY" description:"Key to remove"`
+	} `positional-args:"yes" required:"yes"`
+	Input     string `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
+	PoDir     string `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
+	Template  string `short:"t" long:"pot" description:"POT template to update (skipped if empty or missing)"`
+	SourceDir string `short:"s" long:"source" description:"Source tree to report remaining #KEY and $KEY references in"`
+	DryRun    bool   `short:"D" long:"dry-run" description:"Show what would change without writing files"`
+}
+
+// Execute removes the key everywhere it is defined.
+func (cmd *RemoveCmd) Execute(_ []string) error {
+	key := cmd.Args.Key
+	if key == "" {
+		return fmt.Errorf("KEY is required")
+	}
+
+	rows, err := csvutil.LoadCSV(cmd.Input)
+	if err != nil {
+		return fmt.Errorf("failed to load CSV: %w", err)
+	}
+
+	rowIdx := findKeyRow(rows, key)
+	if rowIdx < 0 {
+		return fmt.Errorf("key '%s' not found in %s", key, cmd.Input)
+	}
+
+	rows = append(rows[:rowIdx], rows[rowIdx+1:]...)
+	fmt.Printf("csv %s: remove %s (row %d)\n", cmd.Input, key, rowIdx+1)
+	if err := writeEditedCSV(cmd.Input, rows, cmd.DryRun); err != nil {
+		return err
+	}
+
+	remove := func(po *poutil.File) int {
+		kept := po.Entries[:0]
+		for _, entry := range po.Entries {
+			if entry.Context != key {
+				kept = append(kept, entry)
+			}
+		}
+		count := len(po.Entries) - len(kept)
+		po.Entries = kept
+		return count
+	}
+	if err := editKeyFiles(cmd.Input, cmd.PoDir, cmd.Template, remove, cmd.DryRun); err != nil {
+		return err
+	}
+
+	if cmd.SourceDir == "" {
+		return nil
+	}
+
+	return srcscan.WalkSources(cmd.SourceDir, func(path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		for _, line := range srcscan.FindKeyRefLines(string(data), key) {
+			fmt.Printf("src %s:%d: %s is still referenced\n", path, line, key)
+		}
+		return nil
+	})
+}
+
+// findKeyRow returns the index of the CSV row with the given key, or -1.
+func findKeyRow(rows [][]string, key string) int {
+	for i := 1; i < len(rows); i++ {
+		if len(rows[i]) > 0 && rows[i][0] == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// writeEditedCSV writes rows back to the CSV file unless dryRun is set.
+func writeEditedCSV(path string, rows [][]string, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	if err := csvutil.WriteFile(path, marshalCSVRows(rows, "\n"), true); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// editKeyFiles applies edit to every PO file in poDir and to the POT
+// template (if it exists), reporting and saving files that changed.
+func editKeyFiles(csvPath, poDir, potPath string, edit func(po *poutil.File) int, dryRun bool) error {
+	poFiles, err := listPOFiles(poDir)
+	if err != nil {
+		return err
+	}
+
+	for _, lang := range selectLangsInOrder(poFiles, nil) {
+		path := poFiles[lang]
+		po, err := poutil.ParseFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		count := edit(po)
+		if count == 0 {
+			continue
+		}
+		fmt.Printf("po %s: %d entries\n", path, count)
+		if dryRun {
+			continue
+		}
+		po.UpdateBuildHeaders("")
+		if err := writePO(path, po); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+	}
+
+	if potPath == "" {
+		return nil
+	}
+	if _, err := os.Stat(potPath); err == nil {
+		return editKeyTemplate(csvPath, potPath, edit, dryRun)
+	}
+	return nil
+}
+
+// editKeyTemplate applies edit to the POT template and refreshes its
+// X-CSV-Hash from csvPath so that it stays in sync with the edited CSV.
+func editKeyTemplate(csvPath, potPath string, edit func(po *poutil.File) int, dryRun bool) error {
+	pot, err := poutil.ParseFile(potPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", potPath, err)
+	}
+	count := edit(pot)
+	if count == 0 {
+		return nil
+	}
+	fmt.Printf("pot %s: %d entries\n", potPath, count)
+	if dryRun {
+		return nil
+	}
+
+	pot.UpdateBuildHeaders("")
+	if csvHash, err := csvutil.ComputeCSVHash(csvPath); err == nil {
+		pot.SetHeader("POT-Creation-Date", time.Now().UTC().Format("2006-01-02 15:04-0700"))
+		pot.SetHeader("X-CSV-Hash", fmt.Sprintf("%016x", csvHash))
+	}
+	if err := writePO(filepath.Clean(potPath), pot); err != nil {
+		return fmt.Errorf("write %s: %w", potPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// writeRenameFixture creates a CSV, a PO directory and a source tree for key edit tests.
+func writeRenameFixture(t *testing.T) (csvPath, poDir, srcDir string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	csvContent := `"Language","original","russian",
+"STR_Old","Old text","",
+"STR_Keep","Keep","",
+`
+	csvPath = filepath.Join(tmpDir, "stringtable.csv")
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	poDir = filepath.Join(tmpDir, "po")
+	if err := os.Mkdir(poDir, 0o755); err != nil {
+		t.Fatalf("failed to create po dir: %v", err)
+	}
+	po := poutil.NewFile()
+	po.Language = "russian"
+	po.SetHeader("Language", "russian")
+	po.Entries = append(po.Entries,
+		&poutil.Entry{Context: "STR_Old", MsgID: "Old text", MsgStr: "Старый текст", Comments: []string{"# note"}},
+		&poutil.Entry{Context: "STR_Keep", MsgID: "Keep", MsgStr: "Оставить"},
+	)
+	data, err := po.MarshalText()
+	if err != nil {
+		t.Fatalf("failed to marshal PO: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(poDir, "russian.po"), data, 0o644); err != nil {
+		t.Fatalf("failed to write PO: %v", err)
+	}
+
+	srcDir = filepath.Join(tmpDir, "scripts")
+	if err := os.Mkdir(srcDir, 0o755); err != nil {
+		t.Fatalf("failed to create src dir: %v", err)
+	}
+	script := "w.SetText(\"#STR_Old\");\nw.SetText(\"#STR_Older\");\n"
+	if err := os.WriteFile(filepath.Join(srcDir, "menu.c"), []byte(script), 0o644); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	return csvPath, poDir, srcDir
+}
+
+func TestRenameCmd(t *testing.T) {
+	csvPath, poDir, srcDir := writeRenameFixture(t)
+
+	cmd := &RenameCmd{Input: csvPath, PoDir: poDir, SourceDir: srcDir}
+	cmd.Args.Old = "STR_Old"
+	cmd.Args.New = "STR_New"
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("RenameCmd.Execute failed: %v", err)
+	}
+
+	csvData, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	wantCSV := `"Language","original","russian",
+"STR_New","Old text","",
+"STR_Keep","Keep","",
+`
+	if string(csvData) != wantCSV {
+		t.Errorf("unexpected CSV:\n%s\nexpected:\n%s", csvData, wantCSV)
+	}
+
+	po, err := poutil.ParseFile(filepath.Join(poDir, "russian.po"))
+	if err != nil {
+		t.Fatalf("failed to parse PO: %v", err)
+	}
+	entry := po.GetEntry("STR_New", "Old text")
+	if entry == nil {
+		t.Fatal("renamed entry not found in PO")
+	}
+	if entry.MsgStr != "Старый текст" {
+		t.Errorf("msgstr not preserved: got %q", entry.MsgStr)
+	}
+	if len(entry.Comments) != 1 || entry.Comments[0] != "# note" {
+		t.Errorf("comments not preserved: got %v", entry.Comments)
+	}
+	if po.GetEntry("STR_Old", "Old text") != nil {
+		t.Error("old entry still present in PO")
+	}
+
+	script, err := os.ReadFile(filepath.Join(srcDir, "menu.c"))
+	if err != nil {
+		t.Fatalf("failed to read script: %v", err)
+	}
+	if !strings.Contains(string(script), `"#STR_New"`) {
+		t.Errorf("reference not rewritten:\n%s", script)
+	}
+	if !strings.Contains(string(script), `"#STR_Older"`) {
+		t.Errorf("unrelated reference was rewritten:\n%s", script)
+	}
+}
+
+func TestRenameCmd_DryRun(t *testing.T) {
+	csvPath, poDir, srcDir := writeRenameFixture(t)
+	before, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+
+	cmd := &RenameCmd{Input: csvPath, PoDir: poDir, SourceDir: srcDir, DryRun: true}
+	cmd.Args.Old = "STR_Old"
+	cmd.Args.New = "STR_New"
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("RenameCmd.Execute failed: %v", err)
+	}
+
+	after, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	if string(before) != string(after) {
+		t.Errorf("dry run modified CSV:\n%s", after)
+	}
+	po, err := poutil.ParseFile(filepath.Join(poDir, "russian.po"))
+	if err != nil {
+		t.Fatalf("failed to parse PO: %v", err)
+	}
+	if po.GetEntry("STR_Old", "Old text") == nil {
+		t.Error("dry run modified PO")
+	}
+}
+
+func TestRenameCmd_ExistingKey(t *testing.T) {
+	csvPath, poDir, _ := writeRenameFixture(t)
+
+	cmd := &RenameCmd{Input: csvPath, PoDir: poDir}
+	cmd.Args.Old = "STR_Old"
+	cmd.Args.New = "STR_Keep"
+	if err := cmd.Execute(nil); err == nil {
+		t.Fatal("expected error when renaming to an existing key")
+	}
+}
+
+func TestRemoveCmd(t *testing.T) {
+	csvPath, poDir, srcDir := writeRenameFixture(t)
+
+	cmd := &RemoveCmd{Input: csvPath, PoDir: poDir, SourceDir: srcDir}
+	cmd.Args.Key = "STR_Old"
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("RemoveCmd.Execute failed: %v", err)
+	}
+
+	csvData, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	if strings.Contains(string(csvData), "STR_Old") {
+		t.Errorf("key not removed from CSV:\n%s", csvData)
+	}
+
+	po, err := poutil.ParseFile(filepath.Join(poDir, "russian.po"))
+	if err != nil {
+		t.Fatalf("failed to parse PO: %v", err)
+	}
+	if po.GetEntry("STR_Old", "Old text") != nil {
+		t.Error("entry not removed from PO")
+	}
+	if po.GetEntry("STR_Keep", "Keep") == nil {
+		t.Error("unrelated entry removed from PO")
+	}
+}
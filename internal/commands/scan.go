@@ -0,0 +1,218 @@
+package commands
+
+//lint:file-ignore SA5008 go-flags requires duplicate choice tags on struct fields
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/woozymasta/dayz-stringtable/internal/csvutil"
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+	"github.com/woozymasta/dayz-stringtable/internal/srcscan"
+)
+
+// ScanCmd scans mod sources for key references and compares them with the CSV.
+//
+// Usage: dayz-stringtable scan --input stringtable.csv --source ./scripts [--prefix STR_] [--ignore-prefix STR_USRACT_] [--format json]
+type ScanCmd struct {
+	Input          string   `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
+	SourceDir      string   `short:"s" long:"source" description:"Mod source directory to scan" default:"."`
+	Format         string   `short:"f" long:"format" description:"Output format" default:"text" choice:"text" choice:"json"`
+	Prefixes       []string `short:"p" long:"prefix" description:"Key prefixes recognized in sources (repeatable, default STR_)"`
+	IgnorePrefixes []string `short:"x" long:"ignore-prefix" description:"Key prefixes provided by the game or other mods, never reported missing (repeatable)"`
+	NoUsages       bool     `short:"U" long:"no-usages" description:"Don't list where each key is used"`
+	Strict         bool     `long:"strict" description:"Exit with error if missing or unused keys are found"`
+}
+
+// ScanReport holds the result of comparing source references with the CSV.
+type ScanReport struct {
+	Usages  map[string][]srcscan.Reference `json:"usages,omitempty"` // Key -> places it is used
+	Missing []string                       `json:"missing"`          // Keys used in sources but absent from CSV
+	Unused  []string                       `json:"unused"`           // Keys in CSV that nothing references
+}
+
+// Execute scans the source tree and prints missing, unused and used keys.
+func (cmd *ScanCmd) Execute(_ []string) error {
+	rows, err := csvutil.LoadCSV(cmd.Input)
+	if err != nil {
+		return fmt.Errorf("failed to load CSV: %w", err)
+	}
+
+	keys, err := csvKeySet(rows)
+	if err != nil {
+		return err
+	}
+
+	refs, err := scanSources(cmd.SourceDir, keys, cmd.Prefixes)
+	if err != nil {
+		return err
+	}
+
+	report := buildScanReport(rows, refs, cmd.IgnorePrefixes)
+	if cmd.NoUsages {
+		report.Usages = nil
+	}
+
+	if cmd.Format == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		printScanReport(report)
+	}
+
+	if cmd.Strict && (len(report.Missing) > 0 || len(report.Unused) > 0) {
+		return fmt.Errorf("found %d missing and %d unused keys", len(report.Missing), len(report.Unused))
+	}
+	return nil
+}
+
+// csvKeySet returns the set of keys defined in the CSV.
+func csvKeySet(rows [][]string) (map[string]bool, error) {
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("CSV must have header and at least one data row")
+	}
+	keys := make(map[string]bool, len(rows)-1)
+	for _, row := range rows[1:] {
+		if len(row) > 0 && row[0] != "" {
+			keys[row[0]] = true
+		}
+	}
+	return keys, nil
+}
+
+// scanSources runs the source scanner with the known keys and prefixes.
+func scanSources(dir string, keys map[string]bool, prefixes []string) ([]srcscan.Reference, error) {
+	if len(prefixes) == 0 {
+		prefixes = srcscan.DefaultPrefixes
+	}
+	scanner := &srcscan.Scanner{Keys: keys, Prefixes: prefixes}
+	refs, err := scanner.ScanDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan sources: %w", err)
+	}
+	return refs, nil
+}
+
+// buildScanReport groups references by key and finds missing and unused keys.
+// Missing keys are sorted; unused keys follow CSV order.
+func buildScanReport(rows [][]string, refs []srcscan.Reference, ignorePrefixes []string) *ScanReport {
+	report := &ScanReport{
+		Usages:  make(map[string][]srcscan.Reference),
+		Missing: []string{},
+		Unused:  []string{},
+	}
+	for _, ref := range refs {
+		report.Usages[ref.Key] = append(report.Usages[ref.Key], ref)
+	}
+
+	defined := make(map[string]bool, len(rows))
+	for _, row := range rows[1:] {
+		if len(row) == 0 || row[0] == "" {
+			continue
+		}
+		defined[row[0]] = true
+		if _, ok := report.Usages[row[0]]; !ok {
+			report.Unused = append(report.Unused, row[0])
+		}
+	}
+
+	for key := range report.Usages {
+		if defined[key] || hasAnyPrefix(key, ignorePrefixes) {
+			continue
+		}
+		report.Missing = append(report.Missing, key)
+	}
+	sort.Strings(report.Missing)
+
+	return report
+}
+
+// printScanReport prints the report in text format.
+func printScanReport(report *ScanReport) {
+	fmt.Printf("Missing keys (used in sources, not in CSV): %d\n", len(report.Missing))
+	for _, key := range report.Missing {
+		fmt.Printf("  %s\t%s\n", key, formatRefs(report.Usages[key]))
+	}
+
+	fmt.Printf("Unused keys (in CSV, not referenced): %d\n", len(report.Unused))
+	for _, key := range report.Unused {
+		fmt.Printf("  %s\n", key)
+	}
+
+	if report.Usages == nil {
+		return
+	}
+	keys := make([]string, 0, len(report.Usages))
+	for key := range report.Usages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Printf("Usages: %d keys\n", len(keys))
+	for _, key := range keys {
+		fmt.Printf("  %s\t%s\n", key, formatRefs(report.Usages[key]))
+	}
+}
+
+// formatRefs joins references as "path:line" pairs.
+func formatRefs(refs []srcscan.Reference) string {
+	parts := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		parts = append(parts, fmt.Sprintf("%s:%d", ref.Path, ref.Line))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// hasAnyPrefix reports whether key starts with any of the given prefixes.
+func hasAnyPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadSourceRefs scans dir and returns "path:line" locations per key.
+// Returns nil when dir is empty, so callers keep existing references.
+func loadSourceRefs(dir string, rows [][]string, prefixes []string) (map[string][]string, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	keys, err := csvKeySet(rows)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := scanSources(dir, keys, prefixes)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		loc := fmt.Sprintf("%s:%d", ref.Path, ref.Line)
+		if seen[ref.Key+"|"+loc] {
+			continue
+		}
+		seen[ref.Key+"|"+loc] = true
+		byKey[ref.Key] = append(byKey[ref.Key], loc)
+	}
+	return byKey, nil
+}
+
+// applySourceRefs replaces "#:" reference comments of every entry with
+// the scanned locations of its key. Does nothing when refs is nil.
+func applySourceRefs(po *poutil.File, refs map[string][]string) {
+	if refs == nil {
+		return
+	}
+	for _, entry := range po.Entries {
+		entry.SetReferences(refs[entry.Context])
+	}
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/srcscan"
+)
+
+func TestBuildScanReport(t *testing.T) {
+	rows := [][]string{
+		{"Language", "original"},
+		{"STR_Used", "Used"},
+		{"STR_Unused", "Unused"},
+	}
+	refs := []srcscan.Reference{
+		{Key: "STR_Used", Path: "a.c", Line: 1},
+		{Key: "STR_Missing", Path: "a.c", Line: 2},
+		{Key: "STR_USRACT_Open", Path: "b.c", Line: 3},
+		{Key: "STR_Used", Path: "b.c", Line: 4},
+	}
+
+	report := buildScanReport(rows, refs, []string{"STR_USRACT_"})
+
+	if want := []string{"STR_Missing"}; !reflect.DeepEqual(report.Missing, want) {
+		t.Errorf("Missing = %v, want %v", report.Missing, want)
+	}
+	if want := []string{"STR_Unused"}; !reflect.DeepEqual(report.Unused, want) {
+		t.Errorf("Unused = %v, want %v", report.Unused, want)
+	}
+	if got := formatRefs(report.Usages["STR_Used"]); got != "a.c:1, b.c:4" {
+		t.Errorf("usages of STR_Used = %q, want %q", got, "a.c:1, b.c:4")
+	}
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/woozymasta/dayz-stringtable/internal/hunspell"
+	"github.com/woozymasta/dayz-stringtable/internal/qa"
+)
+
+// hunspellLocales maps DayZ languages to Hunspell dictionary names tried
+// in the spell directory. Languages without Hunspell dictionaries
+// (chinese, japanese) are not listed.
+var hunspellLocales = map[string][]string{
+	"english":    {"en_US", "en_GB"},
+	"czech":      {"cs_CZ"},
+	"german":     {"de_DE", "de_DE_frami"},
+	"russian":    {"ru_RU"},
+	"polish":     {"pl_PL"},
+	"hungarian":  {"hu_HU"},
+	"italian":    {"it_IT"},
+	"spanish":    {"es_ES"},
+	"french":     {"fr_FR", "fr"},
+	"portuguese": {"pt_BR", "pt_PT"},
+}
+
+// SpellOptions are the spell checking options of check.
+type SpellOptions struct {
+	Spell       bool              `long:"spell" description:"Check spelling with Hunspell dictionaries"`
+	SpellDir    string            `long:"spell-dir" description:"Directory with Hunspell .aff/.dic files named by language or locale (russian.dic or ru_RU.dic)" default:"dict"`
+	SpellDicts  map[string]string `long:"spell-dict" description:"Dictionary of a language as lang:path/to/ru_RU, 'original' for original texts (repeatable)"`
+	SpellIgnore string            `long:"spell-ignore" description:"Word list accepted in every language (game jargon), one word per line"`
+}
+
+// speller loads dictionaries for langs and the original texts. Returns nil
+// if spell checking is disabled. Languages without a dictionary are
+// reported and skipped, the original texts fall back to the english one.
+func (o *SpellOptions) speller(langs []string) (*qa.Speller, error) {
+	if !o.Spell {
+		return nil, nil
+	}
+
+	s := &qa.Speller{Dictionaries: make(map[string]*hunspell.Dictionary)}
+	if o.SpellIgnore != "" {
+		ignore, err := qa.LoadWordList(o.SpellIgnore)
+		if err != nil {
+			return nil, err
+		}
+		s.Ignore = ignore
+	}
+
+	load := func(lang string) (*hunspell.Dictionary, error) {
+		if dict, ok := s.Dictionaries[lang]; ok {
+			return dict, nil
+		}
+		base := o.findDictionary(lang)
+		if base == "" {
+			return nil, nil
+		}
+		dict, err := hunspell.Load(base+".aff", base+".dic")
+		if err != nil {
+			return nil, fmt.Errorf("dictionary %s for %s: %w", base, lang, err)
+		}
+		s.Dictionaries[lang] = dict
+		return dict, nil
+	}
+
+	for _, lang := range langs {
+		dict, err := load(lang)
+		if err != nil {
+			return nil, err
+		}
+		if dict == nil {
+			fmt.Fprintf(os.Stderr, "WARN: no Hunspell dictionary for %s, spelling is not checked\n", lang)
+		}
+	}
+
+	original, err := load(qa.OriginalLanguage)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil {
+		if original, err = load("english"); err != nil {
+			return nil, err
+		}
+	}
+	if original != nil {
+		s.Dictionaries[qa.OriginalLanguage] = original
+	}
+	return s, nil
+}
+
+// findDictionary returns the path of the dictionary of lang without
+// extension, from --spell-dict or the spell directory, or "" if there is
+// none.
+func (o *SpellOptions) findDictionary(lang string) string {
+	if path, ok := o.SpellDicts[lang]; ok {
+		return strings.TrimSuffix(strings.TrimSuffix(path, ".dic"), ".aff")
+	}
+	if o.SpellDir == "" {
+		return ""
+	}
+
+	for _, name := range append([]string{lang}, hunspellLocales[lang]...) {
+		base := filepath.Join(o.SpellDir, name)
+		if _, err := os.Stat(base + ".dic"); err == nil {
+			return base
+		}
+	}
+	return ""
+}
@@ -12,19 +12,28 @@ import (
 	"text/tabwriter"
 
 	"github.com/woozymasta/dayz-stringtable/internal/csvutil"
+	"github.com/woozymasta/dayz-stringtable/internal/junit"
 	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+	"github.com/woozymasta/dayz-stringtable/internal/qa"
+	"github.com/woozymasta/dayz-stringtable/internal/sarif"
+	"github.com/woozymasta/dayz-stringtable/internal/vars"
 )
 
 // StatsCmd displays translation statistics for PO files.
 //
-// Usage: dayz-stringtable stats --input stringtable.csv --podir l18n [--lang russian] [--verbose] [--format json] [--clear-only]
+// Usage: dayz-stringtable stats --input stringtable.csv --podir l18n [--lang russian] [--verbose] [--format json|sarif|junit] [--threshold 90] [--clear-only] [--fallback chinesesimp:chinese] [--overflow]
 type StatsCmd struct {
-	Input     string   `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
-	PoDir     string   `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
-	Format    string   `short:"f" long:"format" description:"Output format" default:"text" choice:"text" choice:"json"`
-	Langs     []string `short:"l" long:"lang" description:"Filter by specific language (all if empty)"`
-	Verbose   bool     `short:"V" long:"verbose" description:"Show detailed untranslated strings"`
-	ClearOnly bool     `short:"c" long:"clear-only" description:"Don't add notranslate comment, just clear msgstr"`
+	LengthOptions
+
+	Input     string            `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
+	PoDir     string            `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
+	Format    string            `short:"f" long:"format" description:"Output format (sarif and junit list untranslated strings)" default:"text" choice:"text" choice:"json" choice:"sarif" choice:"junit"`
+	Langs     []string          `short:"l" long:"lang" description:"Filter by specific language (all if empty)"`
+	Fallbacks map[string]string `short:"F" long:"fallback" description:"Count untranslated strings covered by fallback languages, as lang:fallback[,fallback] (repeatable)"`
+	Verbose   bool              `short:"V" long:"verbose" description:"Show detailed untranslated strings"`
+	ClearOnly bool              `short:"c" long:"clear-only" description:"Don't add notranslate comment, just clear msgstr"`
+	Overflow  bool              `short:"O" long:"overflow" description:"Count translations exceeding max-length flags, --max-length or --max-ratio"`
+	Threshold float64           `long:"threshold" description:"Translation percentage below which JUnit reports fail (untranslated strings are skipped tests above it)" default:"100"`
 }
 
 // LangStats holds translation statistics for a single language.
@@ -35,6 +44,8 @@ type LangStats struct {
 	Total        int                // Total number of strings
 	Percentage   float64            // Translation completion percentage
 	Remaining    int                // Number of untranslated strings
+	Fallback     int                // Number of untranslated strings covered via fallback languages
+	Overflow     int                // Number of translations exceeding length limits
 }
 
 // UntranslatedItem represents a single untranslated string entry.
@@ -88,10 +99,28 @@ func (cmd *StatsCmd) Execute(_ []string) error {
 		return fmt.Errorf("no PO files found in directory '%s'", cmd.PoDir)
 	}
 
-	allStats := cmd.calculateStats(rows, langs, poMap, poFileMap)
+	fallbacks, err := parseFallbacks(cmd.Fallbacks)
+	if err != nil {
+		return err
+	}
+
+	limits, err := cmd.limits()
+	if err != nil {
+		return err
+	}
 
-	if cmd.Format == "json" {
+	allStats := cmd.calculateStats(rows, langs, poMap, poFileMap, fallbacks)
+	if cmd.Overflow {
+		countOverflows(allStats, rows, poMap, limits)
+	}
+
+	switch cmd.Format {
+	case "json":
 		return cmd.outputJSON(allStats)
+	case "sarif":
+		return cmd.statsSARIF(allStats, poFileMap).Write(os.Stdout)
+	case "junit":
+		return cmd.statsJUnit(allStats, poFileMap).Write(os.Stdout)
 	}
 	return cmd.outputText(allStats)
 }
@@ -142,7 +171,8 @@ func selectLanguagesInOrder(poMap map[string]*poutil.File, filter []string) []st
 }
 
 // calculateStats computes translation statistics for all specified languages.
-func (cmd *StatsCmd) calculateStats(rows [][]string, langs []string, poMap map[string]*poutil.File, poFileMap map[string]string) map[string]*LangStats {
+// Untranslated strings that a fallback language translates are counted in Fallback.
+func (cmd *StatsCmd) calculateStats(rows [][]string, langs []string, poMap map[string]*poutil.File, poFileMap map[string]string, fallbacks fallbackChains) map[string]*LangStats {
 	allStats := make(map[string]*LangStats)
 	totalRows := len(rows) - 1
 
@@ -166,24 +196,17 @@ func (cmd *StatsCmd) calculateStats(rows [][]string, langs []string, poMap map[s
 
 			isTranslated := false
 			if po != nil {
-				// Check if translated (has non-empty msgstr)
-				if po.IsTranslatedC(key, original) {
-					isTranslated = true
-				} else if !cmd.ClearOnly {
-					// If --clear-only is not set, entries with # notranslate comment
-					// are considered translated (they were intentionally marked as not needing translation)
-					entry := po.GetEntry(key, original)
-					if entry != nil && entry.HasNoTranslate() {
-						isTranslated = true
-					}
-				}
+				_, isTranslated = cmd.entryTranslation(po.GetEntry(key, original), original)
 			}
 
 			if isTranslated {
 				stats.Translated++
 			} else {
 				stats.Remaining++
-				if cmd.Verbose {
+				if _, _, ok := fallbacks.resolve(poMap, lang, key, original, cmd.entryTranslation); ok {
+					stats.Fallback++
+				}
+				if cmd.Verbose || cmd.Format == "sarif" || cmd.Format == "junit" {
 					poFile := poFileMap[lang]
 					poLine := findMsgctxtLine(poFile, key)
 					stats.Untranslated = append(stats.Untranslated, UntranslatedItem{
@@ -208,6 +231,40 @@ func (cmd *StatsCmd) calculateStats(rows [][]string, langs []string, poMap map[s
 	return allStats
 }
 
+// entryTranslation returns the text of a PO entry counted as translated:
+// a non-empty msgstr, or the original text of a notranslate entry unless
+// --clear-only is set. Fallback languages are counted by the same rules.
+func (cmd *StatsCmd) entryTranslation(entry *poutil.Entry, original string) (string, bool) {
+	switch {
+	case entry == nil:
+		return "", false
+	case entry.MsgStr != "":
+		return entry.MsgStr, true
+	case entry.HasNoTranslate() && !cmd.ClearOnly:
+		// Intentionally marked as not needing translation
+		return original, true
+	}
+	return "", false
+}
+
+// countOverflows counts translations of CSV strings exceeding length limits.
+func countOverflows(allStats map[string]*LangStats, rows [][]string, poMap map[string]*poutil.File, limits *qa.LengthLimits) {
+	for lang, stats := range allStats {
+		po := poMap[lang]
+		if po == nil {
+			continue
+		}
+		for _, row := range rows[1:] {
+			if len(row) < 2 {
+				continue
+			}
+			if entry := po.GetEntry(row[0], row[1]); entry != nil && limits.Exceeds(entry) {
+				stats.Overflow++
+			}
+		}
+	}
+}
+
 // outputText outputs statistics in human-readable text format.
 // In verbose mode, it shows only untranslated strings in grep -nr format.
 // Otherwise, it displays a formatted table with statistics.
@@ -228,14 +285,29 @@ func (cmd *StatsCmd) outputText(allStats map[string]*LangStats) error {
 		_ = w.Flush()
 	}()
 
-	if _, err := fmt.Fprintln(w, "Language\tTranslated\tTotal\tPercentage\tRemaining"); err != nil {
+	// The fallback column is only shown when fallback chains are configured
+	header := "Language\tTranslated\tTotal\tPercentage\tRemaining"
+	if len(cmd.Fallbacks) > 0 {
+		header += "\tFallback"
+	}
+	if cmd.Overflow {
+		header += "\tOverflow"
+	}
+	if _, err := fmt.Fprintln(w, header); err != nil {
 		return fmt.Errorf("failed to write table header: %w", err)
 	}
 
 	for _, lang := range getSortedLangs(allStats) {
 		stats := allStats[lang]
-		if _, err := fmt.Fprintf(w, "%s\t%d\t%d\t%.1f%%\t%d\n",
-			stats.Language, stats.Translated, stats.Total, stats.Percentage, stats.Remaining); err != nil {
+		row := fmt.Sprintf("%s\t%d\t%d\t%.1f%%\t%d",
+			stats.Language, stats.Translated, stats.Total, stats.Percentage, stats.Remaining)
+		if len(cmd.Fallbacks) > 0 {
+			row += fmt.Sprintf("\t%d", stats.Fallback)
+		}
+		if cmd.Overflow {
+			row += fmt.Sprintf("\t%d", stats.Overflow)
+		}
+		if _, err := fmt.Fprintln(w, row); err != nil {
 			return fmt.Errorf("failed to write table row: %w", err)
 		}
 	}
@@ -261,6 +333,13 @@ func (cmd *StatsCmd) outputJSON(allStats map[string]*LangStats) error {
 			"remaining":  stats.Remaining,
 		}
 
+		if len(cmd.Fallbacks) > 0 {
+			langData["fallback"] = stats.Fallback
+		}
+		if cmd.Overflow {
+			langData["overflow"] = stats.Overflow
+		}
+
 		if cmd.Verbose {
 			langData["untranslated"] = stats.Untranslated
 		}
@@ -279,6 +358,58 @@ func (cmd *StatsCmd) outputJSON(allStats map[string]*LangStats) error {
 	return nil
 }
 
+// statsSARIF converts untranslated strings to a SARIF log, located at the
+// msgctxt line of the PO file, or at the CSV row for strings missing from it.
+func (cmd *StatsCmd) statsSARIF(allStats map[string]*LangStats, poFileMap map[string]string) *sarif.Log {
+	log := sarif.New("dayz-stringtable", vars.Version, vars.URL)
+	log.AddRule("untranslated", "The string is translated or marked notranslate", sarif.LevelWarning)
+	for _, lang := range getSortedLangs(allStats) {
+		for _, item := range allStats[lang].Untranslated {
+			path, line := cmd.untranslatedLocation(item, poFileMap[lang])
+			log.AddResult("untranslated", sarif.LevelWarning,
+				fmt.Sprintf("%s is not translated to %s: %q", item.Key, lang, item.Original), path, line)
+		}
+	}
+	return log
+}
+
+// statsJUnit converts statistics to a JUnit report with a suite per
+// language, a coverage test failing below the threshold and a test per
+// untranslated string, failed below the threshold and skipped above it.
+func (cmd *StatsCmd) statsJUnit(allStats map[string]*LangStats, poFileMap map[string]string) *junit.Report {
+	report := junit.New("dayz-stringtable stats")
+	for _, lang := range getSortedLangs(allStats) {
+		stats := allStats[lang]
+		suite := report.Suite(lang)
+		below := stats.Percentage < cmd.Threshold
+
+		coverage := suite.Add("coverage")
+		if below {
+			coverage.Fail("coverage", fmt.Sprintf("%.1f%% translated, threshold is %.1f%%", stats.Percentage, cmd.Threshold), "")
+		}
+
+		for _, item := range stats.Untranslated {
+			msg := fmt.Sprintf("not translated: %q", item.Original)
+			c := suite.Add(item.Key).At(cmd.untranslatedLocation(item, poFileMap[lang]))
+			if below {
+				c.Fail("untranslated", msg, "")
+			} else {
+				c.Skip(msg)
+			}
+		}
+	}
+	return report
+}
+
+// untranslatedLocation returns the PO file and msgctxt line of an
+// untranslated string, or the CSV file and row if the PO file lacks it.
+func (cmd *StatsCmd) untranslatedLocation(item UntranslatedItem, poFile string) (string, int) {
+	if item.PoLine > 0 {
+		return poFile, item.PoLine
+	}
+	return cmd.Input, item.Row
+}
+
 // findMsgctxtLine finds the line number where msgctxt with the given key is located in a PO file.
 // Returns 0 if the key is not found or if there's an error reading the file.
 func findMsgctxtLine(poFile, key string) int {
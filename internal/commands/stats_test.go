@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/woozymasta/dayz-stringtable/internal/csvutil"
 	"github.com/woozymasta/dayz-stringtable/internal/poutil"
 )
 
@@ -332,6 +333,99 @@ func TestStatsCmd_JSONOutput(t *testing.T) {
 	}
 }
 
+// TestStatsCmd_FallbackCoverage verifies counting of strings covered via fallback languages
+func TestStatsCmd_FallbackCoverage(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	csvContent := `"Language","original"
+"STR_Yes","Yes"
+"STR_No","No"
+`
+	csvPath := filepath.Join(tmpDir, "input.csv")
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	poDir := filepath.Join(tmpDir, "po")
+	if err := os.Mkdir(poDir, 0o755); err != nil {
+		t.Fatalf("failed to create po dir: %v", err)
+	}
+	for lang, msgstr := range map[string]string{"chinese": "是", "chinesesimp": ""} {
+		po := poutil.NewFile()
+		po.Language = lang
+		po.SetHeader("Language", lang)
+		po.SetC("STR_Yes", "Yes", msgstr)
+		data, err := po.MarshalText()
+		if err != nil {
+			t.Fatalf("failed to marshal %s.po: %v", lang, err)
+		}
+		if err := os.WriteFile(filepath.Join(poDir, lang+".po"), data, 0o644); err != nil {
+			t.Fatalf("failed to write %s.po: %v", lang, err)
+		}
+	}
+
+	cmd := &StatsCmd{
+		Input:     csvPath,
+		PoDir:     poDir,
+		Fallbacks: map[string]string{"chinesesimp": "chinese"},
+	}
+	poMap, err := poutil.LoadPODirectory(poDir)
+	if err != nil {
+		t.Fatalf("failed to load PO files: %v", err)
+	}
+	rows, err := csvutil.LoadCSV(csvPath)
+	if err != nil {
+		t.Fatalf("failed to load CSV: %v", err)
+	}
+	fallbacks, err := parseFallbacks(cmd.Fallbacks)
+	if err != nil {
+		t.Fatalf("parseFallbacks failed: %v", err)
+	}
+
+	allStats := cmd.calculateStats(rows, []string{"chinese", "chinesesimp"}, poMap, nil, fallbacks)
+	if got := allStats["chinesesimp"]; got.Remaining != 2 || got.Fallback != 1 {
+		t.Errorf("chinesesimp: expected remaining=2 fallback=1, got remaining=%d fallback=%d", got.Remaining, got.Fallback)
+	}
+	if got := allStats["chinese"]; got.Fallback != 0 {
+		t.Errorf("chinese: expected fallback=0, got %d", got.Fallback)
+	}
+}
+
+// TestCountOverflows verifies counting of translations exceeding length limits
+func TestCountOverflows(t *testing.T) {
+	rows := [][]string{
+		{"Language", "original"},
+		{"STR_HUD_Hot", "Hot"},
+		{"STR_Drink", "Drink water"},
+		{"STR_Yes", "Yes"},
+	}
+	po := poutil.NewFile()
+	po.SetC("STR_HUD_Hot", "Hot", "Жарковато")
+	po.SetC("STR_Drink", "Drink water", "Выпейте воды прямо сейчас")
+	po.SetC("STR_Yes", "Yes", "Да")
+
+	cmd := &StatsCmd{LengthOptions: LengthOptions{
+		MaxLength:      map[string]string{"STR_HUD_": "6"},
+		MaxRatio:       1.5,
+		RatioMinLength: 5,
+	}}
+	limits, err := cmd.limits()
+	if err != nil {
+		t.Fatalf("limits failed: %v", err)
+	}
+
+	allStats := map[string]*LangStats{"russian": {Language: "russian"}}
+	countOverflows(allStats, rows, map[string]*poutil.File{"russian": po}, limits)
+	if got := allStats["russian"].Overflow; got != 2 {
+		t.Errorf("expected 2 overflows, got %d", got)
+	}
+
+	cmd.MaxLength = map[string]string{"STR_HUD_": "x"}
+	if _, err := cmd.limits(); err == nil {
+		t.Error("expected error for invalid max length")
+	}
+}
+
 // TestStatsCmd_LangFilter verifies language filtering
 func TestStatsCmd_LangFilter(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -506,3 +600,87 @@ func TestStatsCmd_InvalidLang(t *testing.T) {
 		t.Errorf("unexpected error message: %v", err)
 	}
 }
+
+// statsReportFixture writes a CSV with three strings and a russian PO file
+// with one translated, one empty and one missing entry, and returns their
+// statistics calculated by cmd.
+func statsReportFixture(t *testing.T, cmd *StatsCmd) (map[string]*LangStats, map[string]string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	csvPath := filepath.Join(tmpDir, "input.csv")
+	csvContent := `"Language","original","russian"
+"STR_Yes","Yes",""
+"STR_No","No",""
+"STR_New","New",""
+`
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	poDir := filepath.Join(tmpDir, "po")
+	if err := os.Mkdir(poDir, 0o755); err != nil {
+		t.Fatalf("failed to create po dir: %v", err)
+	}
+	ruPo := poutil.NewFile()
+	ruPo.Language = "russian"
+	ruPo.SetHeader("Language", "russian")
+	ruPo.SetC("STR_Yes", "Yes", "Да")
+	ruPo.SetC("STR_No", "No", "")
+	// STR_New is missing from the PO file
+	ruData, err := ruPo.MarshalText()
+	if err != nil {
+		t.Fatalf("failed to marshal russian.po: %v", err)
+	}
+	ruPath := filepath.Join(poDir, "russian.po")
+	if err := os.WriteFile(ruPath, ruData, 0o644); err != nil {
+		t.Fatalf("failed to write russian.po: %v", err)
+	}
+
+	cmd.Input, cmd.PoDir = csvPath, poDir
+	poMap, err := poutil.LoadPODirectory(poDir)
+	if err != nil {
+		t.Fatalf("failed to load PO files: %v", err)
+	}
+	rows, err := csvutil.LoadCSV(csvPath)
+	if err != nil {
+		t.Fatalf("failed to load CSV: %v", err)
+	}
+	poFileMap := map[string]string{"russian": ruPath}
+	return cmd.calculateStats(rows, []string{"russian"}, poMap, poFileMap, nil), poFileMap
+}
+
+func TestStatsCmd_SARIF(t *testing.T) {
+	cmd := &StatsCmd{Format: "sarif"}
+	allStats, poFileMap := statsReportFixture(t, cmd)
+	ruPath := poFileMap["russian"]
+
+	results := cmd.statsSARIF(allStats, poFileMap).Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if loc := results[0].Locations[0].PhysicalLocation; !strings.HasSuffix(loc.ArtifactLocation.URI, "russian.po") || loc.Region.StartLine != findMsgctxtLine(ruPath, "STR_No") {
+		t.Errorf("STR_No location = %+v", loc)
+	}
+	if loc := results[1].Locations[0].PhysicalLocation; !strings.HasSuffix(loc.ArtifactLocation.URI, "input.csv") || loc.Region.StartLine != 4 {
+		t.Errorf("STR_New location = %+v", loc)
+	}
+}
+
+func TestStatsCmd_JUnit(t *testing.T) {
+	cmd := &StatsCmd{Format: "junit", Threshold: 50}
+	allStats, poFileMap := statsReportFixture(t, cmd)
+
+	suite := cmd.statsJUnit(allStats, poFileMap).Suites[0]
+	if len(suite.Cases) != 3 || suite.Cases[0].Failure == nil {
+		t.Fatalf("expected failed coverage and 2 untranslated cases, got %+v", suite.Cases)
+	}
+	if c := suite.Cases[2]; c.Name != "STR_New" || c.Failure == nil || c.Line != 4 {
+		t.Errorf("unexpected STR_New case %+v", c)
+	}
+
+	cmd.Threshold = 30
+	suite = cmd.statsJUnit(allStats, poFileMap).Suites[0]
+	if suite.Cases[0].Failure != nil || suite.Cases[1].Skipped == nil {
+		t.Errorf("expected passed coverage and skipped strings above threshold, got %+v", suite.Cases)
+	}
+}
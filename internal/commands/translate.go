@@ -6,23 +6,30 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+	"github.com/woozymasta/dayz-stringtable/internal/qa"
 	"github.com/woozymasta/dayz-stringtable/internal/translate"
 )
 
 // TranslateCmd groups subcommands for machine translation providers.
 //
-// Usage: dayz-stringtable translate [--podir l18n] [--lang russian] [--batch 25] <provider> [provider options]
+// Usage: dayz-stringtable translate [--podir l18n] [--lang russian] [--batch 25] [--glossary glossary.csv] [--no-cache] <provider> [provider options]
 type TranslateCmd struct {
-	Deepl   TranslateDeeplCmd  `command:"deepl" description:"Translate using DeepL"`
-	OpenAI  TranslateOpenAICmd `command:"openai" description:"Translate using OpenAI-compatible API"`
-	Google  TranslateGoogleCmd `command:"google" description:"Translate using Google Translate"`
-	PoDir   string             `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
-	Langs   []string           `short:"l" long:"lang" description:"Filter by languages (repeatable)"`
-	Exclude []string           `short:"e" long:"exclude-lang" description:"Exclude languages (repeatable)"`
-	Batch   int                `short:"b" long:"batch" description:"Strings per request batch" default:"25"`
-	DryRun  bool               `short:"D" long:"dry-run" description:"Show what would be translated without calling providers"`
+	Deepl     TranslateDeeplCmd  `command:"deepl" description:"Translate using DeepL"`
+	OpenAI    TranslateOpenAICmd `command:"openai" description:"Translate using OpenAI-compatible API"`
+	Google    TranslateGoogleCmd `command:"google" description:"Translate using Google Translate"`
+	Cache     TranslateCacheCmd  `command:"cache" description:"Show or prune the translation cache"`
+	PoDir     string             `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
+	Langs     []string           `short:"l" long:"lang" description:"Filter by languages (repeatable)"`
+	Exclude   []string           `short:"e" long:"exclude-lang" description:"Exclude languages (repeatable)"`
+	Batch     int                `short:"b" long:"batch" description:"Strings per request batch" default:"25"`
+	Glossary  string             `short:"g" long:"glossary" description:"Glossary CSV, terms found in a batch are passed to the provider"`
+	Attempts  int                `long:"max-attempts" description:"Attempts per request, rate limits, server and network errors are retried with backoff" default:"4"`
+	CacheFile string             `long:"cache-file" description:"Translation cache file (default in the user cache directory)" env:"DAYZ_STRINGTABLE_CACHE"`
+	NoCache   bool               `long:"no-cache" description:"Don't read or write the translation cache"`
+	DryRun    bool               `short:"D" long:"dry-run" description:"Show what would be translated without calling providers"`
 }
 
 // NewTranslateCmd wires shared config into subcommands.
@@ -31,6 +38,8 @@ func NewTranslateCmd() *TranslateCmd {
 	cmd.Deepl.Common = cmd
 	cmd.OpenAI.Common = cmd
 	cmd.Google.Common = cmd
+	cmd.Cache.Stats.Common = cmd
+	cmd.Cache.Prune.Common = cmd
 	return cmd
 }
 
@@ -43,13 +52,14 @@ func (cmd *TranslateCmd) Execute(_ []string) error {
 type TranslateDeeplCmd struct {
 	Common *TranslateCmd `no-flag:"true"`
 
-	AuthKey            string `long:"auth-key" description:"DeepL API auth key" env:"DEEPL_AUTH_KEY" default-mask:"-"`
-	URL                string `long:"url" description:"DeepL API URL (overrides api-free/api)" env:"DEEPL_API_URL"`
-	SourceLang         string `long:"source" description:"Override source language code (e.g. EN)"`
-	Formality          string `long:"formality" description:"Tone, if supported by target language" choice:"default" choice:"less" choice:"more"`
-	SplitSentences     string `long:"split-sentences" description:"Sentence splitting" choice:"0" choice:"1" choice:"nonewlines"`
-	FreeAPI            bool   `long:"api-free" description:"Use api-free.deepl.com endpoint"`
-	PreserveFormatting bool   `long:"preserve-formatting" description:"Preserve formatting"`
+	AuthKey            string  `long:"auth-key" description:"DeepL API auth key" env:"DEEPL_AUTH_KEY" default-mask:"-"`
+	URL                string  `long:"url" description:"DeepL API URL (overrides api-free/api)" env:"DEEPL_API_URL"`
+	SourceLang         string  `long:"source" description:"Override source language code (e.g. EN)"`
+	Formality          string  `long:"formality" description:"Tone, if supported by target language" choice:"default" choice:"less" choice:"more"`
+	SplitSentences     string  `long:"split-sentences" description:"Sentence splitting" choice:"0" choice:"1" choice:"nonewlines"`
+	FreeAPI            bool    `long:"api-free" description:"Use api-free.deepl.com endpoint"`
+	PreserveFormatting bool    `long:"preserve-formatting" description:"Preserve formatting"`
+	RPM                float64 `long:"rpm" description:"Maximum requests per minute (0 for no limit)"`
 }
 
 // Execute runs DeepL translation for selected languages.
@@ -67,6 +77,7 @@ func (cmd *TranslateDeeplCmd) Execute(_ []string) error {
 		PreserveFormatting: cmd.PreserveFormatting,
 		SplitSentences:     cmd.SplitSentences,
 		UseFreeAPI:         cmd.FreeAPI,
+		Retry:              common.retryPolicy(cmd.RPM),
 	}
 
 	return runTranslate(common, client, translate.DeeplTargetLang)
@@ -81,6 +92,7 @@ type TranslateOpenAICmd struct {
 	Model       string  `long:"model" description:"Model name" default:"gpt-4o-mini"`
 	SourceLang  string  `long:"source" description:"Override source language (for prompt)"`
 	Temperature float64 `long:"temperature" description:"Sampling temperature"`
+	RPM         float64 `long:"rpm" description:"Maximum requests per minute (0 for no limit)"`
 }
 
 // Execute runs OpenAI-compatible translation for selected languages.
@@ -95,6 +107,7 @@ func (cmd *TranslateOpenAICmd) Execute(_ []string) error {
 		APIKey:      cmd.APIKey,
 		Model:       cmd.Model,
 		Temperature: cmd.Temperature,
+		Retry:       common.retryPolicy(cmd.RPM),
 	}
 
 	resolve := func(lang string) (string, error) {
@@ -107,10 +120,11 @@ func (cmd *TranslateOpenAICmd) Execute(_ []string) error {
 type TranslateGoogleCmd struct {
 	Common *TranslateCmd `no-flag:"true"`
 
-	APIKey     string `long:"api-key" description:"Google Translate API key" env:"GOOGLE_TRANSLATE_API_KEY" default-mask:"-"`
-	URL        string `long:"url" description:"Google Translate API URL" env:"GOOGLE_TRANSLATE_API_URL"`
-	SourceLang string `long:"source" description:"Override source language code (e.g. en)"`
-	Format     string `long:"format" description:"Text format" choice:"text" choice:"html" default:"text"`
+	APIKey     string  `long:"api-key" description:"Google Translate API key" env:"GOOGLE_TRANSLATE_API_KEY" default-mask:"-"`
+	URL        string  `long:"url" description:"Google Translate API URL" env:"GOOGLE_TRANSLATE_API_URL"`
+	SourceLang string  `long:"source" description:"Override source language code (e.g. en)"`
+	Format     string  `long:"format" description:"Text format" choice:"text" choice:"html" default:"text"`
+	RPM        float64 `long:"rpm" description:"Maximum requests per minute (0 for no limit)"`
 }
 
 // Execute runs Google Translate for selected languages.
@@ -125,6 +139,7 @@ func (cmd *TranslateGoogleCmd) Execute(_ []string) error {
 		APIKey:     cmd.APIKey,
 		SourceLang: cmd.SourceLang,
 		Format:     cmd.Format,
+		Retry:      common.retryPolicy(cmd.RPM),
 	}
 
 	resolve := func(lang string) (string, error) {
@@ -134,6 +149,89 @@ func (cmd *TranslateGoogleCmd) Execute(_ []string) error {
 	return runTranslateWithSource(common, client, resolve, cmd.SourceLang)
 }
 
+// TranslateCacheCmd groups subcommands managing the translation cache.
+type TranslateCacheCmd struct {
+	Stats TranslateCacheStatsCmd `command:"stats" description:"Show cached translations per provider and language"`
+	Prune TranslateCachePruneCmd `command:"prune" description:"Remove cached translations not used recently"`
+}
+
+// TranslateCacheStatsCmd prints translation cache statistics.
+type TranslateCacheStatsCmd struct {
+	Common *TranslateCmd `no-flag:"true"`
+}
+
+// Execute prints the number of cached translations per provider scope and target language.
+func (cmd *TranslateCacheStatsCmd) Execute(_ []string) error {
+	cache, err := cmd.Common.openCache()
+	if err != nil {
+		return err
+	}
+	if cache.Len() == 0 {
+		fmt.Printf("translation cache %s is empty\n", cache.Path())
+		return nil
+	}
+
+	fmt.Printf("translation cache %s: %d entries\n", cache.Path(), cache.Len())
+	for _, s := range cache.Stats() {
+		fmt.Printf("  %s -> %s: %d entries, used %s .. %s\n", s.Scope, s.Target, s.Entries,
+			s.Oldest.Format(time.DateOnly), s.Newest.Format(time.DateOnly))
+	}
+	return nil
+}
+
+// TranslateCachePruneCmd removes old translation cache entries.
+type TranslateCachePruneCmd struct {
+	Common    *TranslateCmd `no-flag:"true"`
+	OlderThan time.Duration `long:"older-than" description:"Remove entries not used for this long" default:"2160h"`
+	All       bool          `long:"all" description:"Remove all entries"`
+}
+
+// Execute removes entries not used within --older-than, or all entries.
+func (cmd *TranslateCachePruneCmd) Execute(_ []string) error {
+	cache, err := cmd.Common.openCache()
+	if err != nil {
+		return err
+	}
+
+	var before time.Time
+	if !cmd.All {
+		before = time.Now().Add(-cmd.OlderThan)
+	}
+	removed := cache.Prune(before)
+	if err := cache.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("pruned %d entries, %d left in %s\n", removed, cache.Len(), cache.Path())
+	return nil
+}
+
+// openCache loads the translation cache from --cache-file or the default path.
+func (cmd *TranslateCmd) openCache() (*translate.Cache, error) {
+	if cmd == nil {
+		return nil, fmt.Errorf("translate command not initialized")
+	}
+	path := cmd.CacheFile
+	if path == "" {
+		var err error
+		if path, err = translate.DefaultCachePath(); err != nil {
+			return nil, err
+		}
+	}
+	return translate.LoadCache(path)
+}
+
+// retryPolicy returns the retry policy of a provider limited to rpm
+// requests per minute, retries are reported on stderr.
+func (cmd *TranslateCmd) retryPolicy(rpm float64) *translate.RetryPolicy {
+	policy := translate.DefaultRetryPolicy()
+	policy.MaxAttempts = cmd.Attempts
+	policy.Limiter = translate.NewRateLimiter(rpm, 1)
+	policy.Notify = func(err error, delay time.Duration, attempt int) {
+		fmt.Fprintf(os.Stderr, "%v, retrying in %s (attempt %d of %d)\n", err, delay.Round(time.Millisecond), attempt, policy.MaxAttempts)
+	}
+	return policy
+}
+
 // requireCommon validates shared translate flags for provider subcommands.
 func requireCommon(common *TranslateCmd) (*TranslateCmd, error) {
 	if common == nil {
@@ -142,6 +240,9 @@ func requireCommon(common *TranslateCmd) (*TranslateCmd, error) {
 	if common.Batch <= 0 {
 		return nil, fmt.Errorf("batch size must be > 0")
 	}
+	if common.Attempts <= 0 {
+		return nil, fmt.Errorf("max attempts must be > 0")
+	}
 	return common, nil
 }
 
@@ -168,6 +269,21 @@ func runTranslateWithSource(common *TranslateCmd, client translate.Client, resol
 		return fmt.Errorf("no languages selected after filters")
 	}
 
+	var glossary *qa.Glossary
+	if common.Glossary != "" {
+		if glossary, err = qa.LoadGlossary(common.Glossary); err != nil {
+			return err
+		}
+	}
+
+	// Cache only clients that describe their options, and not in dry runs
+	var cache *translate.Cache
+	if _, ok := client.(translate.CacheScoper); ok && !common.NoCache && !common.DryRun {
+		if cache, err = common.openCache(); err != nil {
+			return err
+		}
+	}
+
 	ctx := context.Background()
 	total := 0
 	for _, lang := range langs {
@@ -193,7 +309,11 @@ func runTranslateWithSource(common *TranslateCmd, client translate.Client, resol
 			continue
 		}
 
-		translated, err := translatePO(ctx, po, client, sourceLang, target, common.Batch)
+		translated, cached, err := translatePO(ctx, po, client, cache, sourceLang, target, common.Batch, glossary, lang)
+		// Keep translations paid for before a failed batch
+		if cacheErr := saveCache(cache); cacheErr != nil && err == nil {
+			err = cacheErr
+		}
 		if err != nil {
 			return fmt.Errorf("translate %s: %w", lang, err)
 		}
@@ -203,7 +323,11 @@ func runTranslateWithSource(common *TranslateCmd, client translate.Client, resol
 				return fmt.Errorf("write %s: %w", path, err)
 			}
 		}
-		fmt.Printf("lang %s: translated %d\n", lang, translated)
+		if cached > 0 {
+			fmt.Printf("lang %s: translated %d, %d from cache\n", lang, translated, cached)
+		} else {
+			fmt.Printf("lang %s: translated %d\n", lang, translated)
+		}
 		total += translated
 	}
 
@@ -214,8 +338,13 @@ func runTranslateWithSource(common *TranslateCmd, client translate.Client, resol
 }
 
 // translatePO batches untranslated msgid values and writes msgstr responses.
-func translatePO(ctx context.Context, po *poutil.File, client translate.Client, sourceLang, targetLang string, batch int) (int, error) {
-	var pending []*poutil.Entry
+// Identical msgid values are sent once, and texts found in the cache are
+// not sent at all. Glossary terms of lang found in a batch are sent along
+// with it. Returns the number of translated entries and how many of them
+// came from the cache.
+func translatePO(ctx context.Context, po *poutil.File, client translate.Client, cache *translate.Cache, sourceLang, targetLang string, batch int, glossary *qa.Glossary, lang string) (translated, cached int, err error) {
+	var texts []string
+	pending := make(map[string][]*poutil.Entry)
 	for _, entry := range po.Entries {
 		if entry.MsgStr != "" || entry.MsgID == "" {
 			continue
@@ -223,39 +352,97 @@ func translatePO(ctx context.Context, po *poutil.File, client translate.Client,
 		if entry.HasNoTranslate() {
 			continue
 		}
-		pending = append(pending, entry)
+		if _, ok := pending[entry.MsgID]; !ok {
+			texts = append(texts, entry.MsgID)
+		}
+		pending[entry.MsgID] = append(pending[entry.MsgID], entry)
 	}
-	if len(pending) == 0 {
-		return 0, nil
+	if len(texts) == 0 {
+		return 0, 0, nil
 	}
 
-	translated := 0
-	for i := 0; i < len(pending); i += batch {
-		end := i + batch
-		if end > len(pending) {
-			end = len(pending)
+	fill := func(text, translation string) int {
+		for _, entry := range pending[text] {
+			entry.MsgStr = translation
 		}
-		texts := make([]string, 0, end-i)
-		for _, entry := range pending[i:end] {
-			texts = append(texts, entry.MsgID)
+		translated += len(pending[text])
+		return len(pending[text])
+	}
+
+	scope := ""
+	if scoper, ok := client.(translate.CacheScoper); ok && cache != nil {
+		scope = scoper.CacheScope()
+	}
+	cacheKey := func(text string) string {
+		return translate.CacheKey(scope, sourceLang, targetLang, text, batchGlossary(glossary, lang, []string{text}))
+	}
+
+	var missing []string
+	for _, text := range texts {
+		if scope != "" {
+			if translation, ok := cache.Get(cacheKey(text)); ok {
+				cached += fill(text, translation)
+				continue
+			}
 		}
+		missing = append(missing, text)
+	}
+
+	for i := 0; i < len(missing); i += batch {
+		end := i + batch
+		if end > len(missing) {
+			end = len(missing)
+		}
+		texts := missing[i:end]
 		out, err := client.Translate(ctx, translate.Request{
 			SourceLang: sourceLang,
 			TargetLang: targetLang,
 			Texts:      texts,
+			Glossary:   batchGlossary(glossary, lang, texts),
 		})
 		if err != nil {
-			return translated, err
+			return translated, cached, err
 		}
 		if len(out) != len(texts) {
-			return translated, fmt.Errorf("translation response size mismatch: got %d, want %d", len(out), len(texts))
+			return translated, cached, fmt.Errorf("translation response size mismatch: got %d, want %d", len(out), len(texts))
 		}
 		for idx, translatedText := range out {
-			pending[i+idx].MsgStr = translatedText
-			translated++
+			fill(texts[idx], translatedText)
+			if scope != "" && translatedText != "" {
+				cache.Put(cacheKey(texts[idx]), translate.CacheEntry{
+					Text:   translatedText,
+					Scope:  scope,
+					Source: sourceLang,
+					Target: targetLang,
+				})
+			}
+		}
+	}
+	return translated, cached, nil
+}
+
+// saveCache writes the translation cache, if caching is enabled.
+func saveCache(cache *translate.Cache) error {
+	if cache == nil {
+		return nil
+	}
+	return cache.Save()
+}
+
+// batchGlossary returns glossary terms of lang found in texts, each term once.
+func batchGlossary(glossary *qa.Glossary, lang string, texts []string) []translate.GlossaryEntry {
+	var entries []translate.GlossaryEntry
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, term := range glossary.TermsFor(lang, text) {
+			if seen[term.Source] {
+				continue
+			}
+			seen[term.Source] = true
+			entries = append(entries, translate.GlossaryEntry{Source: term.Source, Target: term.Target})
 		}
 	}
-	return translated, nil
+	return entries
 }
 
 // countPending returns the number of untranslated entries and total rune count.
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+	"github.com/woozymasta/dayz-stringtable/internal/translate"
+)
+
+// fakeClient translates texts by prefixing them and records requests.
+type fakeClient struct {
+	requests [][]string
+}
+
+func (c *fakeClient) Translate(_ context.Context, req translate.Request) ([]string, error) {
+	c.requests = append(c.requests, req.Texts)
+	out := make([]string, len(req.Texts))
+	for i, text := range req.Texts {
+		out[i] = "ru:" + text
+	}
+	return out, nil
+}
+
+func (c *fakeClient) CacheScope() string {
+	return "fake"
+}
+
+func TestTranslatePOCache(t *testing.T) {
+	newPO := func() *poutil.File {
+		po := poutil.NewFile()
+		po.SetC("STR_Close", "Close", "")
+		po.SetC("STR_Menu_Close", "Close", "")
+		po.SetC("STR_Cancel", "Cancel", "")
+		return po
+	}
+	cache, err := translate.LoadCache(filepath.Join(t.TempDir(), "cache.json"))
+	if err != nil {
+		t.Fatalf("LoadCache failed: %v", err)
+	}
+
+	client := &fakeClient{}
+	po := newPO()
+	translated, cached, err := translatePO(context.Background(), po, client, cache, "", "Russian", 25, nil, "russian")
+	if err != nil {
+		t.Fatalf("translatePO failed: %v", err)
+	}
+	if translated != 3 || cached != 0 {
+		t.Errorf("translated %d, cached %d, want 3, 0", translated, cached)
+	}
+	if len(client.requests) != 1 || len(client.requests[0]) != 2 {
+		t.Errorf("identical texts not deduplicated: %v", client.requests)
+	}
+	if po.GetC("STR_Menu_Close", "Close") != "ru:Close" {
+		t.Errorf("duplicate entry not filled: %q", po.GetC("STR_Menu_Close", "Close"))
+	}
+
+	client = &fakeClient{}
+	translated, cached, err = translatePO(context.Background(), newPO(), client, cache, "", "Russian", 25, nil, "russian")
+	if err != nil {
+		t.Fatalf("translatePO failed: %v", err)
+	}
+	if translated != 3 || cached != 3 || len(client.requests) != 0 {
+		t.Errorf("translated %d, cached %d, requests %v, want all from cache", translated, cached, client.requests)
+	}
+
+	client = &fakeClient{}
+	if _, _, err := translatePO(context.Background(), newPO(), client, cache, "", "German", 25, nil, "german"); err != nil {
+		t.Fatalf("translatePO failed: %v", err)
+	}
+	if len(client.requests) != 1 {
+		t.Errorf("cache hit for another target language: %v", client.requests)
+	}
+}
@@ -7,24 +7,47 @@ import (
 
 	"github.com/woozymasta/dayz-stringtable/internal/csvutil"
 	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+	"github.com/woozymasta/dayz-stringtable/internal/qa"
 )
 
-// UpdateCmd merges new strings from CSV into existing PO files.
+// UpdateCmd merges new strings from CSV (or a POT template) into existing PO files.
+// PO files of requested languages that don't exist yet are created.
 //
-// Usage: dayz-stringtable update --input stringtable.csv --podir po/ [--langs ru,de] [--outdir updated_po/] [--project-version VERSION]
+// Usage: dayz-stringtable update --input stringtable.csv --podir po/ [--langs ru,de] [--outdir updated_po/] [--project-version VERSION] [--source DIR] [--template stringtable.pot] [--rules notranslate.rules]
 type UpdateCmd struct {
-	Input          string `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
-	PoDir          string `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
-	OutDir         string `short:"o" long:"outdir" description:"Where to write updated PO (defaults to --podir)"`
-	Langs          string `short:"l" long:"langs" description:"Comma-sep langs to update (all if empty)"`
-	ProjectVersion string `short:"P" long:"project-version" description:"Set Project-Id-Version header (project name and version)"`
+	Input          string   `short:"i" long:"input" description:"CSV input file" default:"stringtable.csv"`
+	PoDir          string   `short:"d" long:"podir" description:"Directory for PO files" default:"l18n"`
+	OutDir         string   `short:"o" long:"outdir" description:"Where to write updated PO (defaults to --podir)"`
+	Langs          string   `short:"l" long:"langs" description:"Comma-sep langs to update (all if empty)"`
+	ProjectVersion string   `short:"P" long:"project-version" description:"Set Project-Id-Version header (project name and version)"`
+	Template       string   `short:"t" long:"template" description:"Merge from this POT template instead of the CSV (like msgmerge)"`
+	SourceDir      string   `short:"s" long:"source" description:"Mod source directory to scan for #: references"`
+	Prefixes       []string `long:"prefix" description:"Key prefixes recognized in sources (repeatable, default STR_)"`
+	CommentColumn  string   `long:"comment-column" description:"CSV column with translator notes, emitted as #. comments" default:"comment"`
+	MaxLenColumn   string   `long:"maxlen-column" description:"CSV column with max translation length, emitted as max-length flag" default:"maxlen"`
+	Rules          string   `long:"rules" description:"Do-not-translate rule file, matching entries are marked notranslate"`
 }
 
 // Execute reads CSV and updates each PO file with new entries, preserving existing translations.
 func (cmd *UpdateCmd) Execute(_ []string) error {
-	rows, err := csvutil.LoadCSV(cmd.Input)
-	if err != nil {
-		return fmt.Errorf("failed to load CSV: %w", err)
+	var (
+		rows [][]string
+		pot  *poutil.File
+		err  error
+	)
+	if cmd.Template != "" {
+		if cmd.SourceDir != "" {
+			return fmt.Errorf("--source can't be used with --template, scan sources with 'pot --source' instead")
+		}
+		pot, err = poutil.ParseFile(cmd.Template)
+		if err != nil {
+			return fmt.Errorf("failed to load POT template: %w", err)
+		}
+	} else {
+		rows, err = csvutil.LoadCSV(cmd.Input)
+		if err != nil {
+			return fmt.Errorf("failed to load CSV: %w", err)
+		}
 	}
 
 	outDir := cmd.OutDir
@@ -37,18 +60,23 @@ func (cmd *UpdateCmd) Execute(_ []string) error {
 		return fmt.Errorf("failed to load PO files: %w", err)
 	}
 
-	// Select languages to update
+	// References are refreshed only when sources are scanned,
+	// otherwise existing "#:" comments are kept with other comments
+	refs, err := loadSourceRefs(cmd.SourceDir, rows, cmd.Prefixes)
+	if err != nil {
+		return err
+	}
+
+	rules, err := loadNoTranslateRules(cmd.Rules)
+	if err != nil {
+		return err
+	}
+
+	// Select languages to update, requested languages without
+	// a PO file yet are created and seeded from the CSV like pos does
 	var langs []string
 	if cmd.Langs != "" {
-		langs = ParseLanguages(cmd.Langs)
-		// Filter to only languages that exist
-		filtered := make([]string, 0, len(langs))
-		for _, lang := range langs {
-			if _, ok := poMap[lang]; ok {
-				filtered = append(filtered, lang)
-			}
-		}
-		langs = filtered
+		langs = normalizeLangs([]string{cmd.Langs})
 	} else {
 		for l := range poMap {
 			langs = append(langs, l)
@@ -65,40 +93,19 @@ func (cmd *UpdateCmd) Execute(_ []string) error {
 			for k, v := range existing.Headers {
 				newPo.SetHeader(k, v)
 			}
+		} else if pot != nil && pot.GetHeader("Project-Id-Version") != "" {
+			newPo.SetHeader("Project-Id-Version", pot.GetHeader("Project-Id-Version"))
 		}
 		newPo.SetHeader("Language", lang)
 
-		// CSV format: row[0] = key, row[1] = original text
-		// PO format: msgctxt = key, msgid = original text
-		for _, row := range rows[1:] {
-			if len(row) < 2 {
-				continue
-			}
-
-			key := row[0]
-			original := row[1]
-
-			// Get existing entry to preserve translation and comments
-			var existingEntry *poutil.Entry
-			if existing != nil {
-				existingEntry = existing.GetEntry(key, original)
-			}
-
-			prevMsgStr := ""
-			if existingEntry != nil {
-				prevMsgStr = existingEntry.MsgStr
-			}
-
-			// Set the entry (updates if exists, creates new otherwise)
-			newPo.SetC(key, original, prevMsgStr)
-
-			// Preserve comments from existing entry
-			if existingEntry != nil && len(existingEntry.Comments) > 0 {
-				newEntry := newPo.GetEntry(key, original)
-				if newEntry != nil {
-					newEntry.Comments = existingEntry.Comments
-				}
-			}
+		if pot != nil {
+			mergeTemplateEntries(newPo, existing, pot)
+		} else {
+			cmd.mergeCSVEntries(newPo, existing, rows)
+		}
+		applySourceRefs(newPo, refs)
+		for _, entry := range newPo.Entries {
+			rules.apply(entry)
 		}
 
 		// Update build headers after all entries are added
@@ -114,9 +121,87 @@ func (cmd *UpdateCmd) Execute(_ []string) error {
 		}
 	}
 
+	rules.printReport(os.Stdout)
 	return nil
 }
 
+// mergeCSVEntries adds an entry to newPo for every CSV row, keeping msgstr and
+// comments of the matching existing entry. Entries absent from the CSV are
+// dropped. A new PO file (existing is nil) is seeded from its CSV column.
+func (cmd *UpdateCmd) mergeCSVEntries(newPo, existing *poutil.File, rows [][]string) {
+	seedIdx := -1
+	if existing == nil {
+		for i, h := range rows[0] {
+			if h == newPo.Language {
+				seedIdx = i
+			}
+		}
+	}
+	meta := findMetaColumns(rows[0], cmd.CommentColumn, cmd.MaxLenColumn)
+
+	// CSV format: row[0] = key, row[1] = original text
+	// PO format: msgctxt = key, msgid = original text
+	for _, row := range rows[1:] {
+		if len(row) < 2 {
+			continue
+		}
+
+		key := row[0]
+		original := row[1]
+
+		seed := ""
+		if seedIdx >= 0 && seedIdx < len(row) {
+			seed = row[seedIdx]
+		}
+
+		newEntry := mergeEntry(newPo, existing, key, original, seed)
+		meta.apply(newEntry, row)
+	}
+}
+
+// mergeTemplateEntries adds an entry to newPo for every POT entry, keeping
+// msgstr and translator comments of the matching existing entry, while
+// extracted comments, references and the max-length flag come from the POT.
+// Entries absent from the POT are dropped, as in the CSV path.
+func mergeTemplateEntries(newPo, existing, pot *poutil.File) {
+	for _, tmpl := range pot.Entries {
+		newEntry := mergeEntry(newPo, existing, tmpl.Context, tmpl.MsgID, "")
+
+		newEntry.SetExtractedComments(tmpl.ExtractedComments())
+		newEntry.SetReferences(tmpl.References())
+		newEntry.RemoveFlagValue(qa.MaxLengthFlag)
+		if value, ok := tmpl.FlagValue(qa.MaxLengthFlag); ok {
+			newEntry.AddFlag(qa.MaxLengthFlag + ":" + value)
+		}
+	}
+}
+
+// mergeEntry adds the key/original entry to newPo with msgstr and comments
+// of the existing entry (matched by msgctxt and msgid), or with seed if
+// there is none, and returns it.
+func mergeEntry(newPo, existing *poutil.File, key, original, seed string) *poutil.Entry {
+	// Get existing entry to preserve translation and comments
+	var existingEntry *poutil.Entry
+	if existing != nil {
+		existingEntry = existing.GetEntry(key, original)
+	}
+
+	msgstr := seed
+	if existingEntry != nil {
+		msgstr = existingEntry.MsgStr
+	}
+
+	// Set the entry (updates if exists, creates new otherwise)
+	newPo.SetC(key, original, msgstr)
+
+	// Preserve comments from existing entry
+	newEntry := newPo.GetEntry(key, original)
+	if existingEntry != nil && len(existingEntry.Comments) > 0 {
+		newEntry.Comments = append([]string(nil), existingEntry.Comments...)
+	}
+	return newEntry
+}
+
 // writePOFile writes PO file data to disk, creating parent directories as needed.
 func writePOFile(outDir, lang string, data []byte) error {
 	if outDir == "" {
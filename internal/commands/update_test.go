@@ -122,3 +122,227 @@ func TestUpdateCmd_PreservesComments(t *testing.T) {
 		t.Errorf("Translation for KEY2 not preserved: got %q, want %q", entry2Updated.MsgStr, "Текст 2")
 	}
 }
+
+func TestUpdateCmd_RefreshesSourceReferences(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	csvContent := `"Language","original"
+"STR_Title","Title"
+"STR_Unused","Unused"
+`
+	csvPath := filepath.Join(tmpDir, "input.csv")
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	srcDir := filepath.Join(tmpDir, "scripts")
+	if err := os.MkdirAll(filepath.Join(srcDir, "gui"), 0o755); err != nil {
+		t.Fatalf("failed to create src dir: %v", err)
+	}
+	script := "class MyMenu\n{\n\tvoid Init() { m_Title.SetText(\"#STR_Title\"); }\n}\n"
+	if err := os.WriteFile(filepath.Join(srcDir, "gui", "MyMenu.c"), []byte(script), 0o644); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	poDir := filepath.Join(tmpDir, "po")
+	if err := os.Mkdir(poDir, 0o755); err != nil {
+		t.Fatalf("failed to create po dir: %v", err)
+	}
+	existingPo := poutil.NewFile()
+	existingPo.Language = "russian"
+	existingPo.SetHeader("Language", "russian")
+	existingPo.Entries = append(existingPo.Entries,
+		&poutil.Entry{
+			Context:  "STR_Title",
+			MsgID:    "Title",
+			MsgStr:   "Заголовок",
+			Comments: []string{"# keep it short", "#: gui/Old.c:1"},
+		},
+		&poutil.Entry{
+			Context:  "STR_Unused",
+			MsgID:    "Unused",
+			Comments: []string{"#: gui/Old.c:2"},
+		},
+	)
+	poData, err := existingPo.MarshalText()
+	if err != nil {
+		t.Fatalf("failed to marshal existing PO: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(poDir, "russian.po"), poData, 0o644); err != nil {
+		t.Fatalf("failed to write existing PO: %v", err)
+	}
+
+	cmd := UpdateCmd{Input: csvPath, PoDir: poDir, SourceDir: srcDir}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("UpdateCmd.Execute failed: %v", err)
+	}
+
+	updatedPo, err := poutil.ParseFile(filepath.Join(poDir, "russian.po"))
+	if err != nil {
+		t.Fatalf("failed to parse updated PO: %v", err)
+	}
+
+	title := updatedPo.GetEntry("STR_Title", "Title")
+	if title == nil {
+		t.Fatal("Entry STR_Title not found in updated PO")
+	}
+	want := "# keep it short\n#: gui/MyMenu.c:3"
+	if got := strings.Join(title.Comments, "\n"); got != want {
+		t.Errorf("STR_Title comments = %q, want %q", got, want)
+	}
+	if title.MsgStr != "Заголовок" {
+		t.Errorf("Translation not preserved: got %q", title.MsgStr)
+	}
+
+	unused := updatedPo.GetEntry("STR_Unused", "Unused")
+	if unused == nil {
+		t.Fatal("Entry STR_Unused not found in updated PO")
+	}
+	if len(unused.References()) != 0 {
+		t.Errorf("stale references not removed: %v", unused.Comments)
+	}
+}
+
+func TestUpdateCmd_CreatesMissingLanguages(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	csvContent := `"Language","original","german"
+"KEY1","Text 1","Text 1 de"
+"KEY2","Text 2",""
+`
+	csvPath := filepath.Join(tmpDir, "input.csv")
+	if err := os.WriteFile(csvPath, []byte(csvContent), 0o644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+
+	poDir := filepath.Join(tmpDir, "po")
+	if err := os.Mkdir(poDir, 0o755); err != nil {
+		t.Fatalf("failed to create po dir: %v", err)
+	}
+	existingPo := poutil.NewFile()
+	existingPo.Language = "russian"
+	existingPo.SetHeader("Language", "russian")
+	existingPo.SetC("KEY1", "Text 1", "Текст 1")
+	poData, err := existingPo.MarshalText()
+	if err != nil {
+		t.Fatalf("failed to marshal existing PO: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(poDir, "russian.po"), poData, 0o644); err != nil {
+		t.Fatalf("failed to write existing PO: %v", err)
+	}
+
+	cmd := UpdateCmd{Input: csvPath, PoDir: poDir, Langs: "russian, german"}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("UpdateCmd.Execute failed: %v", err)
+	}
+
+	ruPo, err := poutil.ParseFile(filepath.Join(poDir, "russian.po"))
+	if err != nil {
+		t.Fatalf("failed to parse russian.po: %v", err)
+	}
+	if got := ruPo.GetC("KEY1", "Text 1"); got != "Текст 1" {
+		t.Errorf("russian KEY1 = %q, want %q", got, "Текст 1")
+	}
+	if ruPo.GetEntry("KEY2", "Text 2") == nil {
+		t.Error("russian KEY2 not added")
+	}
+
+	dePo, err := poutil.ParseFile(filepath.Join(poDir, "german.po"))
+	if err != nil {
+		t.Fatalf("german.po not created: %v", err)
+	}
+	if dePo.Language != "german" {
+		t.Errorf("german.po Language = %q", dePo.Language)
+	}
+	if got := dePo.GetC("KEY1", "Text 1"); got != "Text 1 de" {
+		t.Errorf("german KEY1 = %q, want seeded %q", got, "Text 1 de")
+	}
+	if dePo.GetEntry("KEY2", "Text 2") == nil {
+		t.Error("german KEY2 missing")
+	}
+}
+
+func TestUpdateCmd_FromTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	pot := poutil.NewFile()
+	pot.SetHeader("Project-Id-Version", "MyMod 1.0")
+	pot.Entries = append(pot.Entries,
+		&poutil.Entry{
+			Context:  "KEY1",
+			MsgID:    "Text 1",
+			Comments: []string{"#. Shown in the menu", "#: gui/Menu.c:10", "#, max-length:12"},
+		},
+		&poutil.Entry{Context: "KEY3", MsgID: "Text 3"},
+	)
+	potData, err := pot.MarshalText()
+	if err != nil {
+		t.Fatalf("failed to marshal POT: %v", err)
+	}
+	potPath := filepath.Join(tmpDir, "stringtable.pot")
+	if err := os.WriteFile(potPath, potData, 0o644); err != nil {
+		t.Fatalf("failed to write POT: %v", err)
+	}
+
+	poDir := filepath.Join(tmpDir, "po")
+	if err := os.Mkdir(poDir, 0o755); err != nil {
+		t.Fatalf("failed to create po dir: %v", err)
+	}
+	existingPo := poutil.NewFile()
+	existingPo.Language = "russian"
+	existingPo.SetHeader("Language", "russian")
+	existingPo.Entries = append(existingPo.Entries,
+		&poutil.Entry{
+			Context:  "KEY1",
+			MsgID:    "Text 1",
+			MsgStr:   "Текст 1",
+			Comments: []string{"# translator note", "#. stale note", "#: gui/Old.c:1"},
+		},
+		&poutil.Entry{Context: "KEY2", MsgID: "Text 2", MsgStr: "Текст 2"},
+	)
+	poData, err := existingPo.MarshalText()
+	if err != nil {
+		t.Fatalf("failed to marshal existing PO: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(poDir, "russian.po"), poData, 0o644); err != nil {
+		t.Fatalf("failed to write existing PO: %v", err)
+	}
+
+	cmd := UpdateCmd{Template: potPath, PoDir: poDir, Langs: "russian,german"}
+	if err := cmd.Execute(nil); err != nil {
+		t.Fatalf("UpdateCmd.Execute failed: %v", err)
+	}
+
+	ruPo, err := poutil.ParseFile(filepath.Join(poDir, "russian.po"))
+	if err != nil {
+		t.Fatalf("failed to parse russian.po: %v", err)
+	}
+	entry := ruPo.GetEntry("KEY1", "Text 1")
+	if entry == nil {
+		t.Fatal("KEY1 not found")
+	}
+	if entry.MsgStr != "Текст 1" {
+		t.Errorf("msgstr not preserved: %q", entry.MsgStr)
+	}
+	want := "# translator note\n#. Shown in the menu\n#: gui/Menu.c:10\n#, max-length:12"
+	if got := strings.Join(entry.Comments, "\n"); got != want {
+		t.Errorf("KEY1 comments = %q, want %q", got, want)
+	}
+	if ruPo.GetEntry("KEY2", "Text 2") != nil {
+		t.Error("KEY2 absent from POT should be dropped")
+	}
+	if ruPo.GetEntry("KEY3", "Text 3") == nil {
+		t.Error("KEY3 from POT not added")
+	}
+
+	dePo, err := poutil.ParseFile(filepath.Join(poDir, "german.po"))
+	if err != nil {
+		t.Fatalf("german.po not created: %v", err)
+	}
+	if got := dePo.GetHeader("Project-Id-Version"); got != "MyMod 1.0" {
+		t.Errorf("german Project-Id-Version = %q", got)
+	}
+	if len(dePo.Entries) != 2 {
+		t.Errorf("german entries = %d, want 2", len(dePo.Entries))
+	}
+}
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxDiffLines limits the number of removed and added lines shown by checkUpToDate.
+const maxDiffLines = 5
+
+// checkUpToDate compares generated data with the file at path. If they
+// differ it prints a short summary of differing lines and returns an error
+// telling to run the given command.
+func checkUpToDate(path string, data []byte, command string) error {
+	if path == "" {
+		return fmt.Errorf("--check requires --output")
+	}
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err == nil && bytes.Equal(existing, data) {
+		return nil
+	}
+
+	if err != nil {
+		fmt.Printf("%s: missing\n", path)
+	} else {
+		removed, added, first := lineDiff(string(existing), string(data))
+		fmt.Printf("%s: %d lines removed, %d lines added, first difference at line %d\n", path, len(removed), len(added), first)
+		printDiffLines("-", removed)
+		printDiffLines("+", added)
+	}
+	return fmt.Errorf("%s is out of date, run 'dayz-stringtable %s'", path, command)
+}
+
+// maxLCSCells limits the table of the longest common subsequence, larger
+// differences are reported as a whole block of removed and added lines.
+const maxLCSCells = 1 << 22
+
+// lineDiff returns lines of old missing from new and lines of new missing
+// from old in order, so moved lines are both removed and added, and the
+// 1-based number of the first differing line. Lines outside the common
+// prefix and suffix are compared by their longest common subsequence.
+func lineDiff(oldText, newText string) (removed, added []string, first int) {
+	a := strings.Split(oldText, "\n")
+	b := strings.Split(newText, "\n")
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	first = prefix + 1
+
+	if len(a)*len(b) > maxLCSCells {
+		return a, b, first
+	}
+
+	// lcs[i][j] is the length of the common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			removed = append(removed, a[i])
+			i++
+		default:
+			added = append(added, b[j])
+			j++
+		}
+	}
+	removed = append(removed, a[i:]...)
+	added = append(added, b[j:]...)
+	return removed, added, first
+}
+
+// printDiffLines prints up to maxDiffLines lines with the given prefix.
+func printDiffLines(prefix string, lines []string) {
+	for i, line := range lines {
+		if i == maxDiffLines {
+			fmt.Printf("%s ... and %d more\n", prefix, len(lines)-maxDiffLines)
+			return
+		}
+		fmt.Printf("%s %s\n", prefix, line)
+	}
+}
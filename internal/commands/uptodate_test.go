@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name           string
+		oldText        string
+		newText        string
+		removed, added []string
+		first          int
+	}{
+		{"changed", "a\nb\nc\n", "a\nB\nc\n", []string{"b"}, []string{"B"}, 2},
+		{"added", "a\nc\n", "a\nb\nc\n", nil, []string{"b"}, 2},
+		{"reordered", "a\nb\nc\n", "a\nc\nb\n", []string{"b"}, []string{"b"}, 2},
+		{"repeated", "x\nx\n", "x\n", []string{"x"}, nil, 2},
+	}
+	for _, tt := range tests {
+		removed, added, first := lineDiff(tt.oldText, tt.newText)
+		if !reflect.DeepEqual(removed, tt.removed) || !reflect.DeepEqual(added, tt.added) || first != tt.first {
+			t.Errorf("%s: lineDiff() = %q, %q, %d, want %q, %q, %d", tt.name, removed, added, first, tt.removed, tt.added, tt.first)
+		}
+	}
+}
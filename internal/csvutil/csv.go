@@ -3,6 +3,8 @@
 package csvutil
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/csv"
 	"fmt"
 	"io"
@@ -22,10 +24,25 @@ func LoadCSV(path string) ([][]string, error) {
 	}
 	defer func() { _ = f.Close() }()
 
-	rows, err := csv.NewReader(f).ReadAll()
+	return ParseCSV(f)
+}
+
+// ParseCSV reads all records from a reader, with the same validation as LoadCSV.
+// A leading UTF-8 byte order mark is skipped.
+func ParseCSV(reader io.Reader) ([][]string, error) {
+	// Spreadsheet editors like to prepend a UTF-8 BOM
+	r := bufio.NewReader(reader)
+	if bom, err := r.Peek(3); err == nil && string(bom) == "\ufeff" {
+		_, _ = r.Discard(3)
+	}
+
+	rows, err := csv.NewReader(r).ReadAll()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read CSV: %w", err)
 	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("CSV is empty")
+	}
 
 	// Validate: check for duplicate keys (first column)
 	seen := make(map[string]int)
@@ -44,7 +61,9 @@ func LoadCSV(path string) ([][]string, error) {
 }
 
 // WriteFile writes data to stdout if path is empty, or to a file otherwise.
-// If force is false and the file exists, it returns an error.
+// A file that already has exactly this content is not rewritten, so its
+// modification time stays stable. If force is false and the file exists
+// with other content, it returns an error.
 // The function creates parent directories as needed.
 func WriteFile(path string, data []byte, force bool) error {
 	if path == "" {
@@ -52,6 +71,10 @@ func WriteFile(path string, data []byte, force bool) error {
 		return err
 	}
 
+	if upToDate, err := IsUpToDate(path, data); err == nil && upToDate {
+		return nil
+	}
+
 	if !force {
 		if _, err := os.Stat(path); err == nil {
 			return fmt.Errorf("file %s already exists, use --force to overwrite", path)
@@ -96,3 +119,15 @@ func ComputeCSVHash(path string) (uint64, error) {
 
 	return h.Sum64(), nil
 }
+
+// IsUpToDate reports whether the file at path exists with exactly data as content.
+func IsUpToDate(path string, data []byte) (bool, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Equal(existing, data), nil
+}
@@ -0,0 +1,255 @@
+// Package font reads the character to glyph mapping (cmap table) of
+// TrueType and OpenType fonts to tell which characters a font can render.
+package font
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// errTruncated is returned for tables that end before their declared size.
+var errTruncated = errors.New("truncated font data")
+
+// Font is the character coverage of a font.
+type Font struct {
+	glyphs map[rune]bool
+	Name   string // Base name of the font file
+}
+
+// Load reads a .ttf, .otf or .ttc file, the first font of a collection
+// is used.
+func Load(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font: %w", err)
+	}
+	f, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("font %s: %w", path, err)
+	}
+	f.Name = filepath.Base(path)
+	return f, nil
+}
+
+// Parse reads the cmap table of font data. Unicode subtables of all
+// common formats (0, 4, 6, 12, 13) are merged.
+func Parse(data []byte) (*Font, error) {
+	offset := 0
+	if len(data) >= 16 && string(data[:4]) == "ttcf" {
+		// Font collection, use the offset of the first font
+		if binary.BigEndian.Uint32(data[8:12]) == 0 {
+			return nil, fmt.Errorf("empty font collection")
+		}
+		offset = int(binary.BigEndian.Uint32(data[12:16]))
+	}
+
+	cmap, err := findTable(data, offset, "cmap")
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Font{glyphs: make(map[rune]bool)}
+	if len(cmap) < 4 {
+		return nil, errTruncated
+	}
+	numTables := int(binary.BigEndian.Uint16(cmap[2:4]))
+	found := false
+	for i := 0; i < numTables; i++ {
+		rec := 4 + i*8
+		if rec+8 > len(cmap) {
+			return nil, errTruncated
+		}
+		platform := binary.BigEndian.Uint16(cmap[rec:])
+		encoding := binary.BigEndian.Uint16(cmap[rec+2:])
+		if !isUnicode(platform, encoding) {
+			continue
+		}
+
+		sub := int(binary.BigEndian.Uint32(cmap[rec+4:]))
+		if sub+2 > len(cmap) {
+			return nil, errTruncated
+		}
+		ok, err := f.readSubtable(cmap[sub:])
+		if err != nil {
+			return nil, err
+		}
+		found = found || ok
+	}
+	if !found {
+		return nil, fmt.Errorf("no supported Unicode cmap subtable")
+	}
+	return f, nil
+}
+
+// findTable returns the data of a table of the font at offset.
+func findTable(data []byte, offset int, tag string) ([]byte, error) {
+	if offset < 0 || offset+12 > len(data) {
+		return nil, errTruncated
+	}
+	switch string(data[offset : offset+4]) {
+	case "\x00\x01\x00\x00", "OTTO", "true":
+	default:
+		return nil, fmt.Errorf("not a TrueType or OpenType font")
+	}
+
+	numTables := int(binary.BigEndian.Uint16(data[offset+4:]))
+	for i := 0; i < numTables; i++ {
+		rec := offset + 12 + i*16
+		if rec+16 > len(data) {
+			return nil, errTruncated
+		}
+		if string(data[rec:rec+4]) != tag {
+			continue
+		}
+		start := int(binary.BigEndian.Uint32(data[rec+8:]))
+		length := int(binary.BigEndian.Uint32(data[rec+12:]))
+		if start < 0 || length < 0 || start+length > len(data) {
+			return nil, errTruncated
+		}
+		return data[start : start+length], nil
+	}
+	return nil, fmt.Errorf("font has no %s table", tag)
+}
+
+// isUnicode reports whether a cmap encoding record maps Unicode characters.
+func isUnicode(platform, encoding uint16) bool {
+	switch platform {
+	case 0: // Unicode
+		return encoding != 5 // 5 is variation sequences, not a character map
+	case 3: // Windows, Unicode BMP and full repertoire
+		return encoding == 1 || encoding == 10
+	}
+	return false
+}
+
+// readSubtable adds mapped characters of a cmap subtable. Returns false
+// for unsupported formats.
+func (f *Font) readSubtable(b []byte) (bool, error) {
+	switch binary.BigEndian.Uint16(b) {
+	case 0:
+		return true, f.readFormat0(b)
+	case 4:
+		return true, f.readFormat4(b)
+	case 6:
+		return true, f.readFormat6(b)
+	case 12, 13:
+		return true, f.readFormat12(b)
+	}
+	return false, nil
+}
+
+// readFormat0 reads a byte encoding table.
+func (f *Font) readFormat0(b []byte) error {
+	if len(b) < 6+256 {
+		return errTruncated
+	}
+	for c := 0; c < 256; c++ {
+		if b[6+c] != 0 {
+			f.glyphs[rune(c)] = true
+		}
+	}
+	return nil
+}
+
+// readFormat4 reads a segment mapping to delta values table.
+func (f *Font) readFormat4(b []byte) error {
+	if len(b) < 14 {
+		return errTruncated
+	}
+	segCount := int(binary.BigEndian.Uint16(b[6:])) / 2
+	endCodes := 14
+	startCodes := endCodes + segCount*2 + 2
+	idDeltas := startCodes + segCount*2
+	idRangeOffsets := idDeltas + segCount*2
+	if idRangeOffsets+segCount*2 > len(b) {
+		return errTruncated
+	}
+
+	for seg := 0; seg < segCount; seg++ {
+		end := int(binary.BigEndian.Uint16(b[endCodes+seg*2:]))
+		start := int(binary.BigEndian.Uint16(b[startCodes+seg*2:]))
+		delta := int(binary.BigEndian.Uint16(b[idDeltas+seg*2:]))
+		rangeOffsetPos := idRangeOffsets + seg*2
+		rangeOffset := int(binary.BigEndian.Uint16(b[rangeOffsetPos:]))
+
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			glyph := 0
+			if rangeOffset == 0 {
+				glyph = (c + delta) & 0xFFFF
+			} else {
+				pos := rangeOffsetPos + rangeOffset + (c-start)*2
+				if pos+2 > len(b) {
+					return errTruncated
+				}
+				if glyph = int(binary.BigEndian.Uint16(b[pos:])); glyph != 0 {
+					glyph = (glyph + delta) & 0xFFFF
+				}
+			}
+			if glyph != 0 {
+				f.glyphs[rune(c)] = true
+			}
+		}
+	}
+	return nil
+}
+
+// readFormat6 reads a trimmed table mapping.
+func (f *Font) readFormat6(b []byte) error {
+	if len(b) < 10 {
+		return errTruncated
+	}
+	first := int(binary.BigEndian.Uint16(b[6:]))
+	count := int(binary.BigEndian.Uint16(b[8:]))
+	if 10+count*2 > len(b) {
+		return errTruncated
+	}
+	for i := 0; i < count; i++ {
+		if binary.BigEndian.Uint16(b[10+i*2:]) != 0 {
+			f.glyphs[rune(first+i)] = true
+		}
+	}
+	return nil
+}
+
+// readFormat12 reads segmented coverage (format 12) and many-to-one range
+// mapping (format 13) tables, which share their layout.
+func (f *Font) readFormat12(b []byte) error {
+	if len(b) < 16 {
+		return errTruncated
+	}
+	many := binary.BigEndian.Uint16(b) == 13
+	groups := int(binary.BigEndian.Uint32(b[12:]))
+	if groups < 0 || groups > (len(b)-16)/12 {
+		return errTruncated
+	}
+	for i := 0; i < groups; i++ {
+		g := b[16+i*12:]
+		start := binary.BigEndian.Uint32(g)
+		end := binary.BigEndian.Uint32(g[4:])
+		glyph := binary.BigEndian.Uint32(g[8:])
+		if end > 0x10FFFF || start > end {
+			return fmt.Errorf("invalid cmap group %d", i)
+		}
+		for c := start; c <= end; c++ {
+			// Glyph 0 is .notdef, only the first character of a format 12
+			// group can map to it
+			if glyph != 0 || (!many && c != start) {
+				f.glyphs[rune(c)] = true
+			}
+		}
+	}
+	return nil
+}
+
+// Has reports whether the font maps r to a glyph.
+func (f *Font) Has(r rune) bool {
+	return f.glyphs[r]
+}
+
+// Len returns the number of mapped characters.
+func (f *Font) Len() int {
+	return len(f.glyphs)
+}
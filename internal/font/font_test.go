@@ -0,0 +1,92 @@
+package font
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// buildFont returns a font with a cmap table holding the given subtables
+// as Windows Unicode BMP (3,1) and full repertoire (3,10) encodings.
+func buildFont(format4, format12 []byte) []byte {
+	u16 := func(b []byte, v int) []byte { return binary.BigEndian.AppendUint16(b, uint16(v)) }
+	u32 := func(b []byte, v int) []byte { return binary.BigEndian.AppendUint32(b, uint32(v)) }
+
+	var cmap []byte
+	cmap = u16(cmap, 0)
+	cmap = u16(cmap, 2)
+	cmap = u16(u16(cmap, 3), 1)
+	cmap = u32(cmap, 4+2*8)
+	cmap = u16(u16(cmap, 3), 10)
+	cmap = u32(cmap, 4+2*8+len(format4))
+	cmap = append(append(cmap, format4...), format12...)
+
+	var font []byte
+	font = u32(font, 0x00010000)
+	font = u16(font, 1)
+	font = append(font, make([]byte, 6)...)
+	font = append(font, "cmap"...)
+	font = u32(font, 0)
+	font = u32(font, 12+16)
+	font = u32(font, len(cmap))
+	return append(font, cmap...)
+}
+
+func TestParse(t *testing.T) {
+	u16s := func(values ...int) []byte {
+		var b []byte
+		for _, v := range values {
+			b = binary.BigEndian.AppendUint16(b, uint16(v))
+		}
+		return b
+	}
+
+	// A-C by delta, Ж by glyph array (Ж+1 maps to .notdef), end segment
+	var format4 []byte
+	format4 = append(format4, u16s(4, 0, 0, 3*2, 0, 0, 0)...)
+	format4 = append(format4, u16s(0x43, 0x417, 0xFFFF)...) // endCode
+	format4 = append(format4, u16s(0)...)                   // reservedPad
+	format4 = append(format4, u16s(0x41, 0x416, 0xFFFF)...) // startCode
+	format4 = append(format4, u16s(1-0x41, 0, 1)...)        // idDelta
+	format4 = append(format4, u16s(0, 4, 0)...)             // idRangeOffset
+	format4 = append(format4, u16s(4, 0)...)                // glyphIdArray
+	binary.BigEndian.PutUint16(format4[2:], uint16(len(format4)))
+
+	var format12 []byte
+	format12 = append(format12, u16s(12, 0)...)
+	format12 = binary.BigEndian.AppendUint32(format12, 28)
+	format12 = binary.BigEndian.AppendUint32(format12, 0)
+	format12 = binary.BigEndian.AppendUint32(format12, 1)
+	format12 = binary.BigEndian.AppendUint32(format12, 0x4E00)
+	format12 = binary.BigEndian.AppendUint32(format12, 0x4E01)
+	format12 = binary.BigEndian.AppendUint32(format12, 10)
+
+	f, err := Parse(buildFont(format4, format12))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	for _, r := range "ABCЖ一丁" {
+		if !f.Has(r) {
+			t.Errorf("Has(%q) = false, want true", r)
+		}
+	}
+	for _, r := range "DЗ丂" {
+		if f.Has(r) {
+			t.Errorf("Has(%q) = true, want false", r)
+		}
+	}
+	if f.Len() != 6 {
+		t.Errorf("Len = %d, want 6", f.Len())
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"empty":     nil,
+		"not font":  []byte("GIF89a........................"),
+		"truncated": buildFont(nil, nil)[:20],
+	} {
+		if _, err := Parse(data); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
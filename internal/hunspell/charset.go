@@ -0,0 +1,38 @@
+package hunspell
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// charsets maps 8-bit encodings of the SET option to their decoders.
+var charsets = map[string]*charmap.Charmap{
+	"ISO8859-1":        charmap.ISO8859_1,
+	"LATIN1":           charmap.ISO8859_1,
+	"ISO8859-2":        charmap.ISO8859_2,
+	"ISO8859-15":       charmap.ISO8859_15,
+	"KOI8-R":           charmap.KOI8R,
+	"KOI8-U":           charmap.KOI8U,
+	"MICROSOFT-CP1251": charmap.Windows1251,
+}
+
+// decoder returns a function converting text in the SET encoding to UTF-8.
+func decoder(set string) (func([]byte) string, error) {
+	set = strings.ToUpper(strings.TrimSpace(set))
+	if set == "" || set == "UTF-8" {
+		return func(b []byte) string { return string(b) }, nil
+	}
+
+	cm, ok := charsets[set]
+	if !ok {
+		return nil, fmt.Errorf("unsupported encoding '%s', convert the dictionary to UTF-8", set)
+	}
+	dec := cm.NewDecoder()
+	return func(b []byte) string {
+		// Decoding 8-bit charsets never fails, undefined bytes become U+FFFD
+		out, _ := dec.Bytes(b)
+		return string(out)
+	}, nil
+}
@@ -0,0 +1,464 @@
+// Package hunspell checks spelling of words with Hunspell .aff/.dic
+// dictionaries. It supports prefix and suffix rules, twofold suffixes,
+// flag aliases and the common flag formats and encodings, compound words
+// and suggestions are not supported.
+package hunspell
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// affix is a single prefix or suffix rule.
+type affix struct {
+	cond  *regexp.Regexp // Condition on the stem, nil matches any stem
+	flag  string
+	strip string   // Removed from the stem before add is appended
+	add   string   // Added to the stripped stem
+	cont  []string // Continuation flags allowing a second suffix
+	cross bool     // Combines with affixes of the other kind
+}
+
+// Dictionary is a loaded Hunspell dictionary.
+type Dictionary struct {
+	words    map[string][][]string // Word to flag sets of its homonyms
+	prefixes map[string][]*affix   // Prefix rules by added text
+	suffixes map[string][]*affix   // Suffix rules by added text
+	extra    map[string]bool       // Words added at runtime
+	aliases  [][]string            // AF flag aliases
+	flagMode string
+	ignore   string // Characters removed from words before checking
+
+	forbidden    string
+	needAffix    string
+	compoundOnly string
+}
+
+// Load reads a dictionary from .aff and .dic files.
+func Load(affPath, dicPath string) (*Dictionary, error) {
+	aff, err := os.ReadFile(affPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read affix file: %w", err)
+	}
+	dic, err := os.ReadFile(dicPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dictionary: %w", err)
+	}
+	return Parse(aff, dic)
+}
+
+// Parse reads a dictionary from .aff and .dic contents.
+func Parse(aff, dic []byte) (*Dictionary, error) {
+	aff = bytes.TrimPrefix(aff, []byte("\xef\xbb\xbf"))
+	dic = bytes.TrimPrefix(dic, []byte("\xef\xbb\xbf"))
+
+	// The encoding applies to both files, so find it first
+	set := ""
+	for _, line := range bytes.Split(aff, []byte("\n")) {
+		if fields := strings.Fields(string(line)); len(fields) >= 2 && fields[0] == "SET" {
+			set = fields[1]
+			break
+		}
+	}
+	decode, err := decoder(set)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Dictionary{
+		words:    make(map[string][][]string),
+		prefixes: make(map[string][]*affix),
+		suffixes: make(map[string][]*affix),
+		extra:    make(map[string]bool),
+	}
+	if err := d.parseAffixes(decode(aff)); err != nil {
+		return nil, err
+	}
+	if err := d.parseWords(decode(dic)); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// parseAffixes reads options and affix rules of an .aff file.
+func (d *Dictionary) parseAffixes(text string) error {
+	remaining := make(map[string]int) // Rules left to read per affix header
+	cross := make(map[string]bool)
+	aliasCount := -1
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		switch fields[0] {
+		case "FLAG":
+			if len(fields) > 1 {
+				d.flagMode = fields[1]
+			}
+		case "IGNORE":
+			if len(fields) > 1 {
+				d.ignore = fields[1]
+			}
+		case "FORBIDDENWORD", "NEEDAFFIX", "PSEUDOROOT", "ONLYINCOMPOUND":
+			if len(fields) < 2 {
+				continue
+			}
+			switch fields[0] {
+			case "FORBIDDENWORD":
+				d.forbidden = fields[1]
+			case "ONLYINCOMPOUND":
+				d.compoundOnly = fields[1]
+			default:
+				d.needAffix = fields[1]
+			}
+		case "AF":
+			if len(fields) < 2 {
+				continue
+			}
+			// The first AF line is the number of aliases
+			if aliasCount < 0 {
+				if n, err := strconv.Atoi(fields[1]); err == nil {
+					aliasCount = n
+					continue
+				}
+			}
+			d.aliases = append(d.aliases, d.parseFlags(fields[1], false))
+		case "PFX", "SFX":
+			if len(fields) < 4 {
+				return fmt.Errorf("affix file line %d: incomplete %s rule", lineNum, fields[0])
+			}
+			id := fields[0] + " " + fields[1]
+			if remaining[id] == 0 {
+				n, err := strconv.Atoi(fields[3])
+				if err != nil {
+					return fmt.Errorf("affix file line %d: invalid %s header", lineNum, fields[0])
+				}
+				remaining[id] = n
+				cross[id] = fields[2] == "Y"
+				continue
+			}
+			remaining[id]--
+
+			a, err := d.parseAffix(fields, cross[id])
+			if err != nil {
+				return fmt.Errorf("affix file line %d: %w", lineNum, err)
+			}
+			if fields[0] == "PFX" {
+				d.prefixes[a.add] = append(d.prefixes[a.add], a)
+			} else {
+				d.suffixes[a.add] = append(d.suffixes[a.add], a)
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// parseAffix parses a rule line "SFX flag strip add[/flags] [condition]".
+func (d *Dictionary) parseAffix(fields []string, cross bool) (*affix, error) {
+	a := &affix{flag: fields[1], cross: cross}
+	if fields[2] != "0" {
+		a.strip = fields[2]
+	}
+
+	add := fields[3]
+	if i := strings.Index(add, "/"); i >= 0 {
+		a.cont = d.parseFlags(add[i+1:], true)
+		add = add[:i]
+	}
+	if add != "0" {
+		a.add = d.removeIgnored(add)
+	}
+
+	if len(fields) > 4 && fields[4] != "." {
+		cond, err := compileCondition(fields[4], fields[0] == "SFX")
+		if err != nil {
+			return nil, err
+		}
+		a.cond = cond
+	}
+	return a, nil
+}
+
+// compileCondition converts a rule condition ("[^aeiou]y") to a regexp
+// anchored at the end of the stem for suffixes or at its start for prefixes.
+func compileCondition(cond string, suffix bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	inClass, classStart := false, false
+	for _, r := range cond {
+		switch {
+		case r == '[' && !inClass:
+			inClass, classStart = true, true
+			b.WriteRune(r)
+			continue
+		case r == ']' && inClass:
+			inClass = false
+			b.WriteRune(r)
+		case r == '^' && classStart:
+			b.WriteRune(r)
+		case r == '.' && !inClass:
+			b.WriteRune(r)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+		classStart = false
+	}
+
+	pattern := "(?:" + b.String() + ")"
+	if suffix {
+		pattern += "$"
+	} else {
+		pattern = "^" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition '%s': %w", cond, err)
+	}
+	return re, nil
+}
+
+// parseWords reads the word list of a .dic file.
+func (d *Dictionary) parseWords(text string) error {
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	first := true
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		// The first line is the approximate word count
+		if first {
+			first = false
+			if _, err := strconv.Atoi(line); err == nil {
+				continue
+			}
+		}
+
+		// Morphological fields follow the word after a tab or space
+		if i := strings.IndexAny(line, "\t "); i >= 0 {
+			line = line[:i]
+		}
+
+		word, flags := line, ""
+		for i := 0; i < len(line); i++ {
+			if line[i] == '\\' {
+				i++
+				continue
+			}
+			if line[i] == '/' && i > 0 {
+				word, flags = line[:i], line[i+1:]
+				break
+			}
+		}
+		word = d.removeIgnored(strings.ReplaceAll(word, `\/`, "/"))
+		d.words[word] = append(d.words[word], d.parseFlags(flags, true))
+	}
+	return scanner.Err()
+}
+
+// parseFlags splits a flag field according to the FLAG option, numeric
+// fields refer to AF aliases when resolveAliases is set and aliases exist.
+func (d *Dictionary) parseFlags(s string, resolveAliases bool) []string {
+	if s == "" {
+		return nil
+	}
+	if resolveAliases && len(d.aliases) > 0 {
+		if n, err := strconv.Atoi(s); err == nil && n > 0 && n <= len(d.aliases) {
+			return d.aliases[n-1]
+		}
+	}
+
+	var flags []string
+	switch d.flagMode {
+	case "long":
+		runes := []rune(s)
+		for i := 0; i+1 < len(runes); i += 2 {
+			flags = append(flags, string(runes[i:i+2]))
+		}
+	case "num":
+		for _, f := range strings.Split(s, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				flags = append(flags, f)
+			}
+		}
+	default:
+		for _, r := range s {
+			flags = append(flags, string(r))
+		}
+	}
+	return flags
+}
+
+// removeIgnored removes characters of the IGNORE option from s.
+func (d *Dictionary) removeIgnored(s string) string {
+	if d.ignore == "" {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(d.ignore, r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Add adds a word accepted as is, like a personal dictionary entry.
+func (d *Dictionary) Add(word string) {
+	d.extra[word] = true
+}
+
+// Spell reports whether word is spelled correctly. Capitalized and
+// upper case words are also checked in lower and title case.
+func (d *Dictionary) Spell(word string) bool {
+	word = d.removeIgnored(word)
+	if word == "" || d.extra[word] {
+		return true
+	}
+
+	for _, w := range caseVariants(word) {
+		if d.isForbidden(w) {
+			return false
+		}
+		if d.check(w) {
+			return true
+		}
+	}
+	return false
+}
+
+// caseVariants returns word and its lower and title case forms to check.
+func caseVariants(word string) []string {
+	variants := []string{word}
+	first, size := utf8.DecodeRuneInString(word)
+	rest := word[size:]
+	switch {
+	case word == strings.ToUpper(word) && word != strings.ToLower(word):
+		variants = append(variants, strings.ToLower(word), string(first)+strings.ToLower(rest))
+	case unicode.IsUpper(first):
+		variants = append(variants, string(unicode.ToLower(first))+rest)
+	}
+	return variants
+}
+
+// check reports whether word is a dictionary word or derived from one.
+func (d *Dictionary) check(word string) bool {
+	for _, flags := range d.words[word] {
+		if !hasFlag(flags, d.needAffix) && !hasFlag(flags, d.compoundOnly) {
+			return true
+		}
+	}
+	if d.checkSuffix(word, nil, "") {
+		return true
+	}
+	return d.checkPrefix(word)
+}
+
+// isForbidden reports whether word is listed with the FORBIDDENWORD flag.
+func (d *Dictionary) isForbidden(word string) bool {
+	for _, flags := range d.words[word] {
+		if hasFlag(flags, d.forbidden) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPrefix strips prefixes from word and checks the stem, optionally
+// combined with a suffix.
+func (d *Dictionary) checkPrefix(word string) bool {
+	for i := 0; i <= len(word); i++ {
+		if i < len(word) && !utf8.RuneStart(word[i]) {
+			continue
+		}
+		for _, p := range d.prefixes[word[:i]] {
+			stem := p.strip + word[i:]
+			if stem == "" || (p.cond != nil && !p.cond.MatchString(stem)) {
+				continue
+			}
+			if d.hasStem(stem, p.flag, "") {
+				return true
+			}
+			if p.cross && d.checkSuffix(stem, p, "") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkSuffix strips suffixes from word and checks the stem. With prefix
+// set only cross product suffixes are tried and the stem needs both flags.
+// With cont set only suffixes allowing the cont flag as a second suffix
+// are tried.
+func (d *Dictionary) checkSuffix(word string, prefix *affix, cont string) bool {
+	for i := len(word); i >= 0; i-- {
+		if i < len(word) && !utf8.RuneStart(word[i]) {
+			continue
+		}
+		for _, s := range d.suffixes[word[i:]] {
+			if prefix != nil && !s.cross {
+				continue
+			}
+			if cont != "" && !hasFlag(s.cont, cont) {
+				continue
+			}
+			stem := word[:i] + s.strip
+			if stem == "" || (s.cond != nil && !s.cond.MatchString(stem)) {
+				continue
+			}
+
+			prefixFlag := ""
+			if prefix != nil {
+				prefixFlag = prefix.flag
+			}
+			if d.hasStem(stem, s.flag, prefixFlag) {
+				return true
+			}
+			// Twofold suffix, the stem carries an inner suffix that allows this one
+			if prefix == nil && cont == "" && d.checkSuffix(stem, nil, s.flag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasStem reports whether stem is a dictionary word with flag (and with
+// also when set) that isn't forbidden.
+func (d *Dictionary) hasStem(stem, flag, also string) bool {
+	for _, flags := range d.words[stem] {
+		if hasFlag(flags, d.forbidden) {
+			continue
+		}
+		if hasFlag(flags, flag) && (also == "" || hasFlag(flags, also)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFlag reports whether flags contains flag, an empty flag is never set.
+func hasFlag(flags []string, flag string) bool {
+	if flag == "" {
+		return false
+	}
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package hunspell
+
+import "testing"
+
+const testAff = `SET UTF-8
+TRY esianrtolcdugmphbyfvkwz
+FORBIDDENWORD !
+NEEDAFFIX ?
+
+PFX U Y 1
+PFX U   0     un       .
+
+SFX S Y 2
+SFX S   y     ies      [^aeiou]y
+SFX S   0     s        [aeiou]y
+
+SFX D Y 2
+SFX D   0     ed       [^y]
+SFX D   y     ied      y
+
+SFX L N 1
+SFX L   0     ly/S     .
+`
+
+const testDic = `6
+load/UD
+bandage/S
+supply/S
+ammo
+Mosin
+rifl/?D
+`
+
+func TestDictionary_Spell(t *testing.T) {
+	d, err := Parse([]byte(testAff), []byte(testDic))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"load", true},
+		{"loaded", true},
+		{"unload", true},
+		{"unloaded", true},
+		{"supplies", true},
+		{"supplys", false},
+		{"Ammo", true},
+		{"AMMO", true},
+		{"mosin", false},
+		{"MOSIN", true},
+		{"rifl", false},  // NEEDAFFIX stem
+		{"rifled", true}, // derived from NEEDAFFIX stem
+		{"amno", false},
+		{"unammo", false},
+	}
+	for _, tt := range tests {
+		if got := d.Spell(tt.word); got != tt.want {
+			t.Errorf("Spell(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+
+	d.Add("Chernarus")
+	if !d.Spell("Chernarus") {
+		t.Errorf("added word should be accepted")
+	}
+}
+
+func TestParse_Encodings(t *testing.T) {
+	// "молоко" in KOI8-R
+	aff := []byte("SET KOI8-R\n")
+	dic := []byte("1\n\xcd\xcf\xcc\xcf\xcb\xcf\n")
+	d, err := Parse(aff, dic)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !d.Spell("молоко") {
+		t.Errorf("KOI8-R word not decoded")
+	}
+
+	if _, err := Parse([]byte("SET ISCII-DEVANAGARI\n"), nil); err == nil {
+		t.Errorf("expected error for unsupported encoding")
+	}
+}
+
+func TestParse_LongFlagsAndAliases(t *testing.T) {
+	aff := `FLAG long
+AF 1
+AF AaBb
+
+SFX Aa Y 1
+SFX Aa 0 s .
+
+SFX Bb Y 1
+SFX Bb 0 er/Aa .
+`
+	dic := "1\nwalk/1\n"
+	d, err := Parse([]byte(aff), []byte(dic))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	for _, word := range []string{"walk", "walks", "walker", "walkers"} {
+		if !d.Spell(word) {
+			t.Errorf("Spell(%q) = false, want true", word)
+		}
+	}
+	if d.Spell("walkser") {
+		t.Errorf("Spell(walkser) = true, want false")
+	}
+}
@@ -0,0 +1,115 @@
+// Package junit writes test reports in the JUnit XML format understood by
+// CI dashboards (GitLab, Jenkins and others).
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Report is the root testsuites element.
+type Report struct {
+	XMLName  xml.Name `xml:"testsuites"`
+	Name     string   `xml:"name,attr"`
+	Suites   []*Suite `xml:"testsuite"`
+	Tests    int      `xml:"tests,attr"`
+	Failures int      `xml:"failures,attr"`
+	Skipped  int      `xml:"skipped,attr"`
+}
+
+// Suite is a testsuite element.
+type Suite struct {
+	Name     string  `xml:"name,attr"`
+	Cases    []*Case `xml:"testcase"`
+	Tests    int     `xml:"tests,attr"`
+	Failures int     `xml:"failures,attr"`
+	Skipped  int     `xml:"skipped,attr"`
+}
+
+// Case is a testcase element, passed unless Failure or Skipped is set.
+type Case struct {
+	Failure   *Result `xml:"failure,omitempty"`
+	Skipped   *Result `xml:"skipped,omitempty"`
+	Name      string  `xml:"name,attr"`
+	Classname string  `xml:"classname,attr"`
+	File      string  `xml:"file,attr,omitempty"`
+	Line      int     `xml:"line,attr,omitempty"`
+}
+
+// Result is the failure or skip reason of a test case.
+type Result struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// New returns an empty report.
+func New(name string) *Report {
+	return &Report{Name: name}
+}
+
+// Suite returns the suite with name, adding it if it doesn't exist.
+func (r *Report) Suite(name string) *Suite {
+	for _, s := range r.Suites {
+		if s.Name == name {
+			return s
+		}
+	}
+	s := &Suite{Name: name}
+	r.Suites = append(r.Suites, s)
+	return s
+}
+
+// Add adds a test case to the suite and returns it.
+func (s *Suite) Add(name string) *Case {
+	c := &Case{Name: name, Classname: s.Name}
+	s.Cases = append(s.Cases, c)
+	return c
+}
+
+// Fail marks the test case failed.
+func (c *Case) Fail(typ, message, text string) *Case {
+	c.Failure = &Result{Type: typ, Message: message, Text: text}
+	return c
+}
+
+// Skip marks the test case skipped.
+func (c *Case) Skip(message string) *Case {
+	c.Skipped = &Result{Message: message}
+	return c
+}
+
+// At sets the file and line of the test case.
+func (c *Case) At(file string, line int) *Case {
+	c.File, c.Line = file, line
+	return c
+}
+
+// Write updates the counters and writes the report as indented XML.
+func (r *Report) Write(w io.Writer) error {
+	r.Tests, r.Failures, r.Skipped = 0, 0, 0
+	for _, s := range r.Suites {
+		s.Tests, s.Failures, s.Skipped = len(s.Cases), 0, 0
+		for _, c := range s.Cases {
+			switch {
+			case c.Failure != nil:
+				s.Failures++
+			case c.Skipped != nil:
+				s.Skipped++
+			}
+		}
+		r.Tests += s.Tests
+		r.Failures += s.Failures
+		r.Skipped += s.Skipped
+	}
+
+	data, err := xml.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "%s%s\n", xml.Header, data); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package junit
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	report := New("tool")
+	suite := report.Suite("russian")
+	suite.Add("coverage")
+	suite.Add("STR_No").At("l18n/russian.po", 12).Fail("untranslated", "not translated", "")
+	report.Suite("german").Add("STR_Yes").Skip("not translated")
+	if report.Suite("russian") != suite {
+		t.Fatal("Suite added a duplicate suite")
+	}
+
+	var b strings.Builder
+	if err := report.Write(&b); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if !strings.HasPrefix(b.String(), xml.Header) {
+		t.Errorf("missing XML header in %q", b.String())
+	}
+
+	var decoded Report
+	if err := xml.Unmarshal([]byte(b.String()), &decoded); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if decoded.Tests != 3 || decoded.Failures != 1 || decoded.Skipped != 1 {
+		t.Errorf("report counts = %d/%d/%d, want 3/1/1", decoded.Tests, decoded.Failures, decoded.Skipped)
+	}
+	ru := decoded.Suites[0]
+	if ru.Name != "russian" || ru.Tests != 2 || ru.Failures != 1 || ru.Skipped != 0 {
+		t.Errorf("unexpected suite %+v", ru)
+	}
+	if c := ru.Cases[1]; c.Classname != "russian" || c.File != "l18n/russian.po" || c.Line != 12 || c.Failure == nil {
+		t.Errorf("unexpected case %+v", c)
+	}
+}
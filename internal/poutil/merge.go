@@ -0,0 +1,239 @@
+package poutil
+
+import "strings"
+
+// Conflict describes an entry changed differently on both sides of a merge.
+// Ours or Theirs is nil when the entry was deleted on that side.
+type Conflict struct {
+	Base   *Entry
+	Ours   *Entry
+	Theirs *Entry
+}
+
+// Merge3 performs an entry-level three-way merge of PO files.
+// Entries are identified by msgctxt and msgid. An entry changed on one side
+// only takes that side's version (including deletion), an entry changed the
+// same way on both sides is taken once. Entries changed differently on both
+// sides, or deleted on one side and changed on the other, are reported as
+// conflicts; the merged file then holds the ours version (or the surviving
+// one) for them, and callers decide how to present the conflict.
+//
+// Entries keep the ours order, entries added only in theirs are inserted after
+// their predecessor in theirs. Headers changed or deleted only in theirs are
+// taken from theirs, all others from ours. Base may be nil (no common ancestor).
+func Merge3(base, ours, theirs *File) (*File, []Conflict) {
+	if base == nil {
+		base = NewFile()
+	}
+
+	baseIdx := indexEntries(base)
+	oursIdx := indexEntries(ours)
+	theirsIdx := indexEntries(theirs)
+
+	merged := NewFile()
+	merged.Language = ours.Language
+	for k, v := range ours.Headers {
+		merged.SetHeader(k, v)
+	}
+	for _, headers := range []map[string]string{base.Headers, theirs.Headers} {
+		for k := range headers {
+			baseValue, inBase := base.Headers[k]
+			oursValue, inOurs := ours.Headers[k]
+			theirsValue, inTheirs := theirs.Headers[k]
+			if inOurs != inBase || oursValue != baseValue || (inTheirs == inBase && theirsValue == baseValue) {
+				continue
+			}
+			if inTheirs {
+				merged.SetHeader(k, theirsValue)
+			} else {
+				delete(merged.Headers, k)
+			}
+		}
+	}
+	if lang := merged.GetHeader("Language"); lang != "" {
+		merged.Language = lang
+	}
+
+	var conflicts []Conflict
+	resolve := func(key string) *Entry {
+		b, o, t := baseIdx[key], oursIdx[key], theirsIdx[key]
+		switch {
+		case entriesEqual(o, t):
+			return o
+		case entriesEqual(o, b):
+			return t
+		case entriesEqual(t, b):
+			return o
+		}
+		if o != nil && t != nil && o.MsgStr == t.MsgStr {
+			// Only comments differ: merge them against base
+			var baseComments []string
+			if b != nil {
+				baseComments = b.Comments
+			}
+			return &Entry{
+				Context:  o.Context,
+				MsgID:    o.MsgID,
+				MsgStr:   o.MsgStr,
+				Comments: mergeComments(baseComments, o.Comments, t.Comments),
+			}
+		}
+
+		conflicts = append(conflicts, Conflict{Base: b, Ours: o, Theirs: t})
+		if o != nil {
+			return cloneEntry(o)
+		}
+		return cloneEntry(t)
+	}
+
+	// Entries of ours in order
+	position := make(map[string]int)
+	for _, entry := range ours.Entries {
+		key := entry.Key()
+		if resolved := resolve(key); resolved != nil {
+			position[key] = len(merged.Entries)
+			merged.Entries = append(merged.Entries, resolved)
+		}
+	}
+
+	// Entries only in theirs, placed after their predecessor in theirs
+	prevKey := ""
+	for _, entry := range theirs.Entries {
+		key := entry.Key()
+		if _, ok := oursIdx[key]; !ok {
+			if resolved := resolve(key); resolved != nil {
+				at := len(merged.Entries)
+				if idx, ok := position[prevKey]; ok && prevKey != "" {
+					at = idx + 1
+				}
+				merged.Entries = append(merged.Entries, nil)
+				copy(merged.Entries[at+1:], merged.Entries[at:])
+				merged.Entries[at] = resolved
+				for k, idx := range position {
+					if idx >= at {
+						position[k] = idx + 1
+					}
+				}
+				position[key] = at
+			}
+		}
+		if _, ok := position[key]; ok {
+			prevKey = key
+		}
+	}
+
+	return merged, conflicts
+}
+
+// indexEntries maps entry keys to entries.
+func indexEntries(f *File) map[string]*Entry {
+	idx := make(map[string]*Entry, len(f.Entries))
+	for _, entry := range f.Entries {
+		idx[entry.Key()] = entry
+	}
+	return idx
+}
+
+// entriesEqual compares msgstr and comments of two entries (nil-safe).
+func entriesEqual(a, b *Entry) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.MsgStr != b.MsgStr || len(a.Comments) != len(b.Comments) {
+		return false
+	}
+	for i := range a.Comments {
+		if a.Comments[i] != b.Comments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeComments merges comment lines of both sides against base: lines
+// either side removed from base are dropped and lines either side added are
+// kept, in ours order followed by lines added in theirs. "#," flags are
+// merged the same way as sets of flags and written as a single flag line
+// in place of the first flag line of ours.
+func mergeComments(base, ours, theirs []string) []string {
+	baseLines, baseFlags := commentSets(base)
+	oursLines, oursFlags := commentSets(ours)
+	theirsLines, theirsFlags := commentSets(theirs)
+
+	// keep reports whether an item of ours or theirs survives the merge
+	keep := func(item string, inBase, inOurs, inTheirs map[string]bool) bool {
+		return !inBase[item] || (inOurs[item] && inTheirs[item])
+	}
+
+	var flags []string
+	seenFlags := make(map[string]bool)
+	for _, list := range [][]string{flagsOf(ours), flagsOf(theirs)} {
+		for _, flag := range list {
+			if !seenFlags[flag] && keep(flag, baseFlags, oursFlags, theirsFlags) {
+				seenFlags[flag] = true
+				flags = append(flags, flag)
+			}
+		}
+	}
+	flagLine := ""
+	if len(flags) > 0 {
+		flagLine = "#, " + strings.Join(flags, ", ")
+	}
+
+	var out []string
+	flagsWritten := false
+	for _, c := range ours {
+		if isFlagComment(c) {
+			if !flagsWritten && flagLine != "" {
+				out = append(out, flagLine)
+			}
+			flagsWritten = true
+			continue
+		}
+		if keep(c, baseLines, oursLines, theirsLines) {
+			out = append(out, c)
+		}
+	}
+	for _, c := range theirs {
+		if !isFlagComment(c) && !oursLines[c] && !baseLines[c] {
+			out = append(out, c)
+		}
+	}
+	if !flagsWritten && flagLine != "" {
+		out = append(out, flagLine)
+	}
+	return out
+}
+
+// commentSets returns the set of non-flag comment lines and the set of
+// flags of comments.
+func commentSets(comments []string) (lines, flags map[string]bool) {
+	lines = make(map[string]bool)
+	flags = make(map[string]bool)
+	for _, c := range comments {
+		if !isFlagComment(c) {
+			lines[c] = true
+		}
+	}
+	for _, flag := range flagsOf(comments) {
+		flags[flag] = true
+	}
+	return lines, flags
+}
+
+// flagsOf returns the flags of "#," comments in order.
+func flagsOf(comments []string) []string {
+	return (&Entry{Comments: comments}).Flags()
+}
+
+// isFlagComment reports whether a comment line is a "#," flag comment.
+func isFlagComment(c string) bool {
+	return strings.HasPrefix(strings.TrimSpace(c), "#,")
+}
+
+// cloneEntry returns a copy of the entry that can be modified safely.
+func cloneEntry(e *Entry) *Entry {
+	c := *e
+	c.Comments = append([]string(nil), e.Comments...)
+	return &c
+}
@@ -0,0 +1,150 @@
+package poutil
+
+import "testing"
+
+// mergeFixture builds a PO file with the given key/msgstr pairs (msgid = key).
+func mergeFixture(pairs ...string) *File {
+	f := NewFile()
+	f.Language = "russian"
+	f.SetHeader("Language", "russian")
+	for i := 0; i+1 < len(pairs); i += 2 {
+		f.Entries = append(f.Entries, &Entry{Context: pairs[i], MsgID: pairs[i], MsgStr: pairs[i+1]})
+	}
+	return f
+}
+
+func TestMerge3_NonOverlapping(t *testing.T) {
+	base := mergeFixture("A", "", "B", "", "C", "")
+	ours := mergeFixture("A", "a", "B", "", "C", "")
+	theirs := mergeFixture("A", "", "B", "b", "C", "", "D", "d")
+
+	merged, conflicts := Merge3(base, ours, theirs)
+	if len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts, got %d", len(conflicts))
+	}
+
+	want := []string{"A=a", "B=b", "C=", "D=d"}
+	if len(merged.Entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(merged.Entries))
+	}
+	for i, entry := range merged.Entries {
+		if got := entry.Context + "=" + entry.MsgStr; got != want[i] {
+			t.Errorf("entry %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestMerge3_InsertsTheirsAfterPredecessor(t *testing.T) {
+	base := mergeFixture("A", "", "C", "")
+	ours := mergeFixture("A", "", "C", "")
+	theirs := mergeFixture("A", "", "B", "b", "C", "")
+
+	merged, _ := Merge3(base, ours, theirs)
+	if len(merged.Entries) != 3 || merged.Entries[1].Context != "B" {
+		t.Fatalf("expected B between A and C, got %+v", merged.Entries)
+	}
+}
+
+func TestMerge3_Deletion(t *testing.T) {
+	base := mergeFixture("A", "a", "B", "b")
+	ours := mergeFixture("A", "a")
+	theirs := mergeFixture("A", "a", "B", "b")
+
+	merged, conflicts := Merge3(base, ours, theirs)
+	if len(conflicts) != 0 || len(merged.Entries) != 1 {
+		t.Fatalf("expected deletion to win, got %d entries and %d conflicts", len(merged.Entries), len(conflicts))
+	}
+}
+
+func TestMerge3_Conflict(t *testing.T) {
+	base := mergeFixture("A", "")
+	ours := mergeFixture("A", "ours")
+	theirs := mergeFixture("A", "theirs")
+
+	merged, conflicts := Merge3(base, ours, theirs)
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %d", len(conflicts))
+	}
+	if conflicts[0].Ours.MsgStr != "ours" || conflicts[0].Theirs.MsgStr != "theirs" {
+		t.Errorf("unexpected conflict sides: %+v", conflicts[0])
+	}
+	if merged.Entries[0].MsgStr != "ours" {
+		t.Errorf("expected merged entry to keep ours, got %q", merged.Entries[0].MsgStr)
+	}
+}
+
+func TestMerge3_CommentsOnly(t *testing.T) {
+	base := mergeFixture("A", "a")
+	ours := mergeFixture("A", "a")
+	ours.Entries[0].Comments = []string{"# ours note"}
+	theirs := mergeFixture("A", "a")
+	theirs.Entries[0].Comments = []string{"#: scripts/menu.c:1"}
+
+	merged, conflicts := Merge3(base, ours, theirs)
+	if len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts, got %d", len(conflicts))
+	}
+	if got := len(merged.Entries[0].Comments); got != 2 {
+		t.Errorf("expected comments from both sides, got %v", merged.Entries[0].Comments)
+	}
+}
+
+func TestMerge3_TheirsHeaders(t *testing.T) {
+	base := mergeFixture()
+	base.SetHeader("Last-Translator", "a")
+	ours := mergeFixture()
+	ours.SetHeader("Last-Translator", "a")
+	theirs := mergeFixture()
+	theirs.SetHeader("Last-Translator", "b")
+
+	merged, _ := Merge3(base, ours, theirs)
+	if got := merged.GetHeader("Last-Translator"); got != "b" {
+		t.Errorf("Last-Translator = %q, want %q", got, "b")
+	}
+}
+
+func TestMerge3_RemovedFlag(t *testing.T) {
+	base := mergeFixture("A", "a")
+	base.Entries[0].Comments = []string{"# note", "#, fuzzy"}
+	ours := mergeFixture("A", "a")
+	ours.Entries[0].Comments = []string{"# note"}
+	theirs := mergeFixture("A", "a")
+	theirs.Entries[0].Comments = []string{"# note", "#: scripts/menu.c:1", "#, fuzzy, max-length:12"}
+
+	merged, conflicts := Merge3(base, ours, theirs)
+	if len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts, got %d", len(conflicts))
+	}
+	entry := merged.Entries[0]
+	if entry.HasFlag("fuzzy") {
+		t.Errorf("fuzzy removed in ours came back: %v", entry.Comments)
+	}
+	want := []string{"# note", "#: scripts/menu.c:1", "#, max-length:12"}
+	if len(entry.Comments) != len(want) {
+		t.Fatalf("comments = %q, want %q", entry.Comments, want)
+	}
+	for i := range want {
+		if entry.Comments[i] != want[i] {
+			t.Errorf("comments = %q, want %q", entry.Comments, want)
+			break
+		}
+	}
+}
+
+func TestMerge3_DeletedHeader(t *testing.T) {
+	base := mergeFixture()
+	base.SetHeader("Last-Translator", "a")
+	base.SetHeader("Language-Team", "team")
+	ours := mergeFixture()
+	ours.SetHeader("Last-Translator", "a")
+	ours.SetHeader("Language-Team", "new team")
+	theirs := mergeFixture()
+
+	merged, _ := Merge3(base, ours, theirs)
+	if _, ok := merged.Headers["Last-Translator"]; ok {
+		t.Errorf("header deleted in theirs kept: %v", merged.Headers)
+	}
+	if got := merged.GetHeader("Language-Team"); got != "new team" {
+		t.Errorf("Language-Team = %q, want ours change kept", got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -106,6 +107,11 @@ func (f *File) GetEntry(context, msgid string) *Entry {
 	return nil
 }
 
+// Key identifies an entry by msgctxt and msgid, as gettext does.
+func (e *Entry) Key() string {
+	return e.Context + "\x04" + e.MsgID
+}
+
 // HasNoTranslate checks if an entry has the "notranslate" flag in its comments.
 func (e *Entry) HasNoTranslate() bool {
 	for _, comment := range e.Comments {
@@ -125,6 +131,192 @@ func (e *Entry) HasNoTranslate() bool {
 	return false
 }
 
+// References returns the locations listed in "#:" reference comments.
+func (e *Entry) References() []string {
+	var refs []string
+	for _, comment := range e.Comments {
+		trimmed := strings.TrimSpace(comment)
+		if strings.HasPrefix(trimmed, "#:") {
+			refs = append(refs, strings.Fields(strings.TrimPrefix(trimmed, "#:"))...)
+		}
+	}
+	return refs
+}
+
+// SetReferences replaces all "#:" reference comments with one comment per location.
+// Other comments are kept in place; references are inserted after translator
+// and extracted comments and before flag comments, as gettext orders them.
+func (e *Entry) SetReferences(refs []string) {
+	kept := make([]string, 0, len(e.Comments)+len(refs))
+	insertAt := -1
+	for _, comment := range e.Comments {
+		trimmed := strings.TrimSpace(comment)
+		if strings.HasPrefix(trimmed, "#:") {
+			if insertAt < 0 {
+				insertAt = len(kept)
+			}
+			continue
+		}
+		if insertAt < 0 && (strings.HasPrefix(trimmed, "#,") || strings.HasPrefix(trimmed, "#|")) {
+			insertAt = len(kept)
+		}
+		kept = append(kept, comment)
+	}
+	if insertAt < 0 {
+		insertAt = len(kept)
+	}
+
+	lines := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		lines = append(lines, "#: "+ref)
+	}
+
+	comments := make([]string, 0, len(kept)+len(lines))
+	comments = append(comments, kept[:insertAt]...)
+	comments = append(comments, lines...)
+	comments = append(comments, kept[insertAt:]...)
+	if len(comments) == 0 {
+		comments = nil
+	}
+	e.Comments = comments
+}
+
+// Flags returns all flags from "#," flag comments in order.
+func (e *Entry) Flags() []string {
+	var flags []string
+	for _, comment := range e.Comments {
+		trimmed := strings.TrimSpace(comment)
+		if !strings.HasPrefix(trimmed, "#,") {
+			continue
+		}
+		for _, flag := range strings.Split(strings.TrimPrefix(trimmed, "#,"), ",") {
+			if flag = strings.TrimSpace(flag); flag != "" {
+				flags = append(flags, flag)
+			}
+		}
+	}
+	return flags
+}
+
+// HasFlag checks if an entry has the exact flag in its "#," flag comments.
+func (e *Entry) HasFlag(flag string) bool {
+	for _, item := range e.Flags() {
+		if item == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// FlagValue returns the value of a "name:value" flag (e.g. "max-length:24")
+// and whether the flag is present.
+func (e *Entry) FlagValue(name string) (string, bool) {
+	for _, item := range e.Flags() {
+		if value, ok := strings.CutPrefix(item, name+":"); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// AddFlag adds a flag to the first "#," flag comment, creating it if needed.
+// Does nothing if the flag is already present.
+func (e *Entry) AddFlag(flag string) {
+	if e.HasFlag(flag) {
+		return
+	}
+	for i, comment := range e.Comments {
+		if strings.HasPrefix(strings.TrimSpace(comment), "#,") {
+			e.Comments[i] = strings.TrimRight(comment, " ") + ", " + flag
+			return
+		}
+	}
+	e.Comments = append(e.Comments, "#, "+flag)
+}
+
+// RemoveFlag removes a flag from all "#," flag comments.
+// A flag comment left without flags is dropped.
+func (e *Entry) RemoveFlag(flag string) {
+	e.removeFlags(func(item string) bool { return item == flag })
+}
+
+// RemoveFlagValue removes all "name:value" flags with the given name.
+func (e *Entry) RemoveFlagValue(name string) {
+	e.removeFlags(func(item string) bool { return strings.HasPrefix(item, name+":") })
+}
+
+// removeFlags rewrites "#," flag comments without flags matching drop.
+func (e *Entry) removeFlags(drop func(string) bool) {
+	comments := e.Comments[:0]
+	for _, comment := range e.Comments {
+		trimmed := strings.TrimSpace(comment)
+		if !strings.HasPrefix(trimmed, "#,") {
+			comments = append(comments, comment)
+			continue
+		}
+		var kept []string
+		for _, flag := range strings.Split(strings.TrimPrefix(trimmed, "#,"), ",") {
+			if flag = strings.TrimSpace(flag); flag != "" && !drop(flag) {
+				kept = append(kept, flag)
+			}
+		}
+		if len(kept) > 0 {
+			comments = append(comments, "#, "+strings.Join(kept, ", "))
+		}
+	}
+	if len(comments) == 0 {
+		comments = nil
+	}
+	e.Comments = comments
+}
+
+// ExtractedComments returns the text of "#." extracted comments.
+func (e *Entry) ExtractedComments() []string {
+	var out []string
+	for _, comment := range e.Comments {
+		trimmed := strings.TrimSpace(comment)
+		if strings.HasPrefix(trimmed, "#.") {
+			out = append(out, strings.TrimSpace(strings.TrimPrefix(trimmed, "#.")))
+		}
+	}
+	return out
+}
+
+// SetExtractedComments replaces all "#." extracted comments with the given lines.
+// They are inserted after translator comments and before references and flags.
+func (e *Entry) SetExtractedComments(lines []string) {
+	kept := make([]string, 0, len(e.Comments)+len(lines))
+	insertAt := -1
+	for _, comment := range e.Comments {
+		trimmed := strings.TrimSpace(comment)
+		if strings.HasPrefix(trimmed, "#.") {
+			if insertAt < 0 {
+				insertAt = len(kept)
+			}
+			continue
+		}
+		if insertAt < 0 && (strings.HasPrefix(trimmed, "#:") ||
+			strings.HasPrefix(trimmed, "#,") || strings.HasPrefix(trimmed, "#|")) {
+			insertAt = len(kept)
+		}
+		kept = append(kept, comment)
+	}
+	if insertAt < 0 {
+		insertAt = len(kept)
+	}
+
+	comments := make([]string, 0, len(kept)+len(lines))
+	comments = append(comments, kept[:insertAt]...)
+	for _, line := range lines {
+		comments = append(comments, strings.TrimRight("#. "+line, " "))
+	}
+	comments = append(comments, kept[insertAt:]...)
+	if len(comments) == 0 {
+		comments = nil
+	}
+	e.Comments = comments
+}
+
 // ParseFile reads and parses a PO/POT file from disk.
 func ParseFile(path string) (*File, error) {
 	file, err := os.Open(path)
@@ -444,59 +636,77 @@ func (f *File) MarshalText() ([]byte, error) {
 		}
 	}
 
-	// Write remaining headers
-	for key, value := range f.Headers {
+	// Write remaining headers in sorted order, so unchanged files marshal identically
+	var extra []string
+	for key := range f.Headers {
 		if !written[key] {
-			headerLine := fmt.Sprintf("%s: %s\n", key, value)
-			b.WriteString(`"`)
-			// Escape the header line
-			for _, r := range headerLine {
-				switch r {
-				case '\\':
-					b.WriteString(`\\`)
-				case '"':
-					b.WriteString(`\"`)
-				case '\n':
-					b.WriteString(`\n`)
-				default:
-					b.WriteRune(r)
-				}
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+	for _, key := range extra {
+		value := f.Headers[key]
+		headerLine := fmt.Sprintf("%s: %s\n", key, value)
+		b.WriteString(`"`)
+		// Escape the header line
+		for _, r := range headerLine {
+			switch r {
+			case '\\':
+				b.WriteString(`\\`)
+			case '"':
+				b.WriteString(`\"`)
+			case '\n':
+				b.WriteString(`\n`)
+			default:
+				b.WriteRune(r)
 			}
-			b.WriteString(`"` + "\n")
 		}
+		b.WriteString(`"` + "\n")
 	}
 
 	b.WriteString("\n")
 
 	// Write entries
 	for _, entry := range f.Entries {
-		// Write comments
-		for _, comment := range entry.Comments {
-			b.WriteString(comment)
-			b.WriteString("\n")
-		}
+		writeEntry(&b, entry)
+	}
 
-		// Write msgctxt if present
-		if entry.Context != "" {
-			b.WriteString("msgctxt ")
-			writeQuotedString(&b, entry.Context)
-			b.WriteString("\n")
-		}
+	return []byte(b.String()), nil
+}
 
-		// Write msgid
-		b.WriteString("msgid ")
-		writeQuotedString(&b, entry.MsgID)
-		b.WriteString("\n")
+// MarshalText renders a single entry in PO format, followed by a blank line.
+func (e *Entry) MarshalText() []byte {
+	var b strings.Builder
+	writeEntry(&b, e)
+	return []byte(b.String())
+}
 
-		// Write msgstr
-		b.WriteString("msgstr ")
-		writeQuotedString(&b, entry.MsgStr)
+// writeEntry writes comments, msgctxt, msgid and msgstr of an entry.
+func writeEntry(b *strings.Builder, entry *Entry) {
+	// Write comments
+	for _, comment := range entry.Comments {
+		b.WriteString(comment)
 		b.WriteString("\n")
+	}
 
+	// Write msgctxt if present
+	if entry.Context != "" {
+		b.WriteString("msgctxt ")
+		writeQuotedString(b, entry.Context)
 		b.WriteString("\n")
 	}
 
-	return []byte(b.String()), nil
+	// Write msgid
+	b.WriteString("msgid ")
+	writeQuotedString(b, entry.MsgID)
+	b.WriteString("\n")
+
+	// Write msgstr
+	b.WriteString("msgstr ")
+	writeQuotedString(b, entry.MsgStr)
+	b.WriteString("\n")
+
+	b.WriteString("\n")
 }
 
 // writeQuotedString writes a string in PO format (with proper escaping).
@@ -572,17 +782,36 @@ func (f *File) GetHeader(key string) string {
 // This is used to detect if the file content has actually changed, so we can
 // avoid updating PO-Revision-Date when only dates have changed.
 func (f *File) computeContentHash() uint64 {
+	return f.hashContent(false)
+}
+
+// MatchesContentHash reports whether stored, an X-Content-Hash value, is the
+// hash of the current content. Hashes written by versions that included
+// X-Generator in the hash are accepted as well.
+func (f *File) MatchesContentHash(stored string) bool {
+	var hash uint64
+	if _, err := fmt.Sscanf(stored, "%x", &hash); err != nil {
+		return false
+	}
+	return hash == f.hashContent(false) || hash == f.hashContent(true)
+}
+
+// hashContent hashes the content for computeContentHash, withGenerator
+// includes the X-Generator header like earlier versions did.
+func (f *File) hashContent(withGenerator bool) uint64 {
 	h := xxhash.New()
 
 	// Hash language
 	_, _ = io.WriteString(h, f.Language)
 	_, _ = io.WriteString(h, "\n")
 
-	// Hash all headers except date-related ones
+	// Hash all headers except date-related ones and the tool version,
+	// upgrading the tool doesn't change the content
 	excludedHeaders := map[string]bool{
 		"PO-Revision-Date":  true,
 		"POT-Creation-Date": true,
 		"X-Content-Hash":    true, // Exclude hash itself
+		"X-Generator":       !withGenerator,
 	}
 
 	// Sort headers for consistent hashing
@@ -634,9 +863,6 @@ func (f *File) computeContentHash() uint64 {
 func (f *File) UpdateBuildHeaders(projectVersion string) {
 	buildInfo := vars.Info()
 
-	// Set or update X-Generator
-	f.SetHeader("X-Generator", fmt.Sprintf("dayz-stringtable %s", buildInfo.Version))
-
 	// Set Project-Id-Version if provided
 	// Note: Project-Id-Version should contain the project name and version being translated,
 	// not the tool version. It should be set manually by the user if needed.
@@ -644,18 +870,15 @@ func (f *File) UpdateBuildHeaders(projectVersion string) {
 		f.SetHeader("Project-Id-Version", projectVersion)
 	}
 
+	// Check if content has changed, before X-Generator is updated as
+	// hashes written by earlier versions include it
+	contentChanged := !f.MatchesContentHash(f.GetHeader("X-Content-Hash"))
+
+	// Set or update X-Generator
+	f.SetHeader("X-Generator", fmt.Sprintf("dayz-stringtable %s", buildInfo.Version))
+
 	// Compute hash of current content (before updating dates)
 	newHash := f.computeContentHash()
-	oldHashStr := f.GetHeader("X-Content-Hash")
-
-	// Check if content has changed
-	contentChanged := true
-	if oldHashStr != "" {
-		var oldHash uint64
-		if _, err := fmt.Sscanf(oldHashStr, "%x", &oldHash); err == nil {
-			contentChanged = (newHash != oldHash)
-		}
-	}
 
 	// Update POT-Creation-Date or PO-Revision-Date only if content changed
 	if contentChanged {
@@ -670,7 +893,8 @@ func (f *File) UpdateBuildHeaders(projectVersion string) {
 			// PO file
 			f.SetHeader("PO-Revision-Date", now)
 		}
-		// Save new hash
-		f.SetHeader("X-Content-Hash", fmt.Sprintf("%016x", newHash))
 	}
+
+	// Save new hash, also replacing an equivalent hash of an earlier version
+	f.SetHeader("X-Content-Hash", fmt.Sprintf("%016x", newHash))
 }
@@ -1,6 +1,7 @@
 package poutil
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -442,6 +443,25 @@ func TestUpdateBuildHeadersNoChange(t *testing.T) {
 	}
 }
 
+func TestUpdateBuildHeaders_LegacyHash(t *testing.T) {
+	// Hashes of earlier versions include X-Generator
+	f := NewFile()
+	f.Language = "ru"
+	f.SetHeader("Language", "ru")
+	f.SetHeader("X-Generator", "dayz-stringtable 0.3.1")
+	f.SetHeader("PO-Revision-Date", "2026-01-28 10:00+0000")
+	f.SetC("key1", "msg1", "trans1")
+	f.SetHeader("X-Content-Hash", fmt.Sprintf("%016x", f.hashContent(true)))
+
+	f.UpdateBuildHeaders("")
+	if got := f.GetHeader("PO-Revision-Date"); got != "2026-01-28 10:00+0000" {
+		t.Errorf("PO-Revision-Date bumped for a legacy hash of unchanged content: %q", got)
+	}
+	if got, want := f.GetHeader("X-Content-Hash"), fmt.Sprintf("%016x", f.computeContentHash()); got != want {
+		t.Errorf("X-Content-Hash = %q, want the current hash %q", got, want)
+	}
+}
+
 func TestUpdateBuildHeaders_ProjectVersion(t *testing.T) {
 	// Test that Project-Id-Version is set when provided
 	f := NewFile()
@@ -461,3 +481,78 @@ func TestUpdateBuildHeaders_ProjectVersion(t *testing.T) {
 		t.Errorf("Project-Id-Version = %q, want empty", proj2)
 	}
 }
+
+func TestEntry_SetReferences(t *testing.T) {
+	e := &Entry{
+		Context: "KEY",
+		MsgID:   "Text",
+		Comments: []string{
+			"# translator note",
+			"#. extracted",
+			"#: old/file.c:1",
+			"#, notranslate",
+		},
+	}
+
+	e.SetReferences([]string{"gui/menu.c:42", "config.cpp:7"})
+
+	want := []string{
+		"# translator note",
+		"#. extracted",
+		"#: gui/menu.c:42",
+		"#: config.cpp:7",
+		"#, notranslate",
+	}
+	if strings.Join(e.Comments, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Comments = %q, want %q", e.Comments, want)
+	}
+	if refs := e.References(); len(refs) != 2 || refs[0] != "gui/menu.c:42" || refs[1] != "config.cpp:7" {
+		t.Errorf("References() = %v", refs)
+	}
+
+	e.SetReferences(nil)
+	if len(e.References()) != 0 {
+		t.Errorf("references not removed: %v", e.Comments)
+	}
+	if len(e.Comments) != 3 {
+		t.Errorf("expected 3 comments after removing references, got %v", e.Comments)
+	}
+}
+
+func TestEntry_Flags(t *testing.T) {
+	e := &Entry{Comments: []string{"# note", "#, notranslate"}}
+
+	e.AddFlag("max-length:24")
+	e.AddFlag("fuzzy")
+	e.AddFlag("fuzzy")
+	if got := strings.Join(e.Comments, "\n"); got != "# note\n#, notranslate, max-length:24, fuzzy" {
+		t.Errorf("after AddFlag: %q", got)
+	}
+	if !e.HasFlag("fuzzy") || e.HasFlag("fuzz") {
+		t.Error("HasFlag() mismatch")
+	}
+	if v, ok := e.FlagValue("max-length"); !ok || v != "24" {
+		t.Errorf("FlagValue() = %q, %v", v, ok)
+	}
+
+	e.RemoveFlagValue("max-length")
+	e.RemoveFlag("notranslate")
+	e.RemoveFlag("fuzzy")
+	if got := strings.Join(e.Comments, "\n"); got != "# note" {
+		t.Errorf("after RemoveFlag: %q", got)
+	}
+}
+
+func TestEntry_SetExtractedComments(t *testing.T) {
+	e := &Entry{Comments: []string{"# note", "#. old", "#: a.c:1", "#, fuzzy"}}
+
+	e.SetExtractedComments([]string{"line 1", "line 2"})
+
+	want := "# note\n#. line 1\n#. line 2\n#: a.c:1\n#, fuzzy"
+	if got := strings.Join(e.Comments, "\n"); got != want {
+		t.Errorf("Comments = %q, want %q", got, want)
+	}
+	if got := e.ExtractedComments(); len(got) != 2 || got[1] != "line 2" {
+		t.Errorf("ExtractedComments() = %v", got)
+	}
+}
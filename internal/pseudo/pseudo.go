@@ -0,0 +1,145 @@
+// Package pseudo generates pseudo-localized text from original strings to
+// find hardcoded strings, clipped widgets and missing glyphs before
+// translators start.
+package pseudo
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/woozymasta/dayz-stringtable/internal/qa"
+)
+
+// Transforms applied to the text between placeholders and markup.
+const (
+	TransformAccents  = "accents"  // Replace ASCII letters with accented ones
+	TransformExpand   = "expand"   // Pad the text by the expansion percentage
+	TransformBrackets = "brackets" // Wrap the text in [ and ]
+	TransformMirror   = "mirror"   // Reverse the characters of every text run
+	TransformRTL      = "rtl"      // Wrap text runs in right-to-left override marks
+)
+
+// Transforms lists the known transforms in the order they are applied.
+var Transforms = []string{TransformAccents, TransformMirror, TransformRTL, TransformExpand, TransformBrackets}
+
+// accents maps ASCII letters to accented letters of the Latin-1 and
+// Latin Extended-A blocks, that fonts of European languages cover.
+var accents = func() map[rune]rune {
+	m := make(map[rune]rune)
+	for _, pair := range [][2]string{
+		{"abcdefghijklmnopqrstuvwxyz", "åbçđéfĝĥîĵķĺmñöpqŕšţûvŵxýž"},
+		{"ABCDEFGHIJKLMNOPQRSTUVWXYZ", "ÅBÇĐÉFĜĤÎĴĶĹMÑÖPQŔŠŢÛVŴXÝŽ"},
+	} {
+		to := []rune(pair[1])
+		for i, r := range pair[0] {
+			if to[i] != r {
+				m[r] = to[i]
+			}
+		}
+	}
+	return m
+}()
+
+// Right-to-left override and pop directional formatting marks.
+const (
+	rlo = '\u202E'
+	pdf = '\u202C'
+)
+
+// Options selects the transforms.
+type Options struct {
+	Accents   bool
+	Mirror    bool
+	RTL       bool
+	Brackets  bool
+	Expansion int // Percentage of padding added to the text length, 0 disables
+}
+
+// ParseOptions returns options enabling the named transforms, expansion
+// is the padding percentage of the expand transform.
+func ParseOptions(transforms []string, expansion int) (Options, error) {
+	var opts Options
+	if expansion < 0 {
+		return opts, fmt.Errorf("expansion must not be negative, got %d", expansion)
+	}
+	for _, name := range transforms {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case TransformAccents:
+			opts.Accents = true
+		case TransformExpand:
+			opts.Expansion = expansion
+		case TransformBrackets:
+			opts.Brackets = true
+		case TransformMirror:
+			opts.Mirror = true
+		case TransformRTL:
+			opts.RTL = true
+		default:
+			return opts, fmt.Errorf("unknown pseudo-localization transform %q (want one of %s)", name, strings.Join(Transforms, ", "))
+		}
+	}
+	return opts, nil
+}
+
+// Apply returns the pseudo-localized text of s. Placeholders, rich text
+// tags and escaped line breaks are kept intact and in place, as are
+// leading and trailing whitespace. Empty text stays empty.
+func (o Options) Apply(s string) string {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return s
+	}
+	start := strings.Index(s, trimmed)
+	lead, trail := s[:start], s[start+len(trimmed):]
+
+	var b strings.Builder
+	letters, pos := 0, 0
+	for _, span := range qa.ProtectedSpans(trimmed) {
+		letters += o.writeText(&b, trimmed[pos:span[0]])
+		b.WriteString(trimmed[span[0]:span[1]])
+		pos = span[1]
+	}
+	letters += o.writeText(&b, trimmed[pos:])
+
+	text := b.String()
+	if o.Expansion > 0 && letters > 0 {
+		// Round up so short strings grow too
+		text += " " + strings.Repeat("~", (letters*o.Expansion+99)/100)
+	}
+	if o.Brackets {
+		text = "[" + text + "]"
+	}
+	return lead + text + trail
+}
+
+// writeText writes a transformed run of text between protected parts and
+// returns the number of its non-space characters.
+func (o Options) writeText(b *strings.Builder, text string) int {
+	if text == "" {
+		return 0
+	}
+	runes := []rune(text)
+	letters := 0
+	for i, r := range runes {
+		if !unicode.IsSpace(r) {
+			letters++
+		}
+		if to, ok := accents[r]; ok && o.Accents {
+			runes[i] = to
+		}
+	}
+	if o.Mirror {
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+	}
+	if o.RTL {
+		b.WriteRune(rlo)
+	}
+	b.WriteString(string(runes))
+	if o.RTL {
+		b.WriteRune(pdf)
+	}
+	return letters
+}
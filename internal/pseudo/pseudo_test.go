@@ -0,0 +1,43 @@
+package pseudo
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		transforms []string
+		in, want   string
+	}{
+		{"accents", []string{"accents"}, "Save game", "Šåvé ĝåmé"},
+		{"placeholders", []string{"accents"}, "Ammo: %1 / %2", "Åmmö: %1 / %2"},
+		{"markup", []string{"accents"}, "<color name='red'>Bad</color>\\nday", "<color name='red'>Båđ</color>\\nđåý"},
+		{"expand", []string{"expand"}, "Save", "Save ~~"},
+		{"brackets", []string{"brackets"}, " Save ", " [Save] "},
+		{"mirror", []string{"mirror"}, "Save <b>game</b>", " evaS<b>emag</b>"},
+		{"rtl", []string{"rtl"}, "Hi %1", "\u202EHi \u202C%1"},
+		{"all", []string{"accents", "expand", "brackets"}, "Ok", "[Öķ ~]"},
+		{"empty", []string{"accents", "brackets"}, "", ""},
+	}
+	for _, tt := range tests {
+		opts, err := ParseOptions(tt.transforms, 50)
+		if err != nil {
+			t.Fatalf("%s: ParseOptions failed: %v", tt.name, err)
+		}
+		if got := opts.Apply(tt.in); got != tt.want {
+			t.Errorf("%s: Apply(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	if _, err := ParseOptions([]string{"upside-down"}, 30); err == nil {
+		t.Error("expected error for unknown transform")
+	}
+	if _, err := ParseOptions([]string{"expand"}, -10); err == nil {
+		t.Error("expected error for negative expansion")
+	}
+	opts, err := ParseOptions([]string{"accents"}, 30)
+	if err != nil || opts.Expansion != 0 {
+		t.Errorf("expansion set without expand transform: %+v, %v", opts, err)
+	}
+}
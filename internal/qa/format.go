@@ -0,0 +1,204 @@
+package qa
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+var (
+	// placeholderPattern matches %1..%9, %s, %d, {0} and {name} placeholders,
+	// an escaped percent sign (%%) is matched to be skipped.
+	placeholderPattern = regexp.MustCompile(`%%|%[1-9sd]|\{[0-9]+\}|\{[A-Za-z_][A-Za-z0-9_]*\}`)
+
+	// tagPattern matches rich text tags like <br/>, <color ...>, </color> and <image .../>.
+	tagPattern = regexp.MustCompile(`<\s*(/?)\s*([A-Za-z][A-Za-z0-9_]*)[^<>]*>`)
+
+	// protectedPattern matches placeholders, rich text tags and escaped
+	// line breaks, the parts of a text that must not be rewritten.
+	protectedPattern = regexp.MustCompile(placeholderPattern.String() + "|" + tagPattern.String() + `|\\n`)
+)
+
+// FormatChecks returns the checks comparing placeholders, markup, line
+// breaks and whitespace of msgstr with msgid.
+func FormatChecks() []Check {
+	return []Check{
+		{
+			Name:        "placeholders",
+			Description: "Placeholders (%1..%9, %s, %d, {0}, {name}) of the translation match the original",
+			Severity:    SeverityError,
+			Run:         checkPlaceholders,
+		},
+		{
+			Name:        "markup",
+			Description: "Rich text tags (<br/>, <color>, <image>) of the translation match the original",
+			Severity:    SeverityError,
+			Run:         checkMarkup,
+		},
+		{
+			Name:        "newlines",
+			Description: "The translation has as many line breaks as the original",
+			Severity:    SeverityWarning,
+			Run:         checkNewlines,
+		},
+		{
+			Name:        "whitespace",
+			Description: "Leading and trailing whitespace of the translation match the original",
+			Severity:    SeverityWarning,
+			Run:         checkWhitespace,
+		},
+		{
+			Name:        "identical",
+			Description: "The translation differs from the original, or the entry is marked notranslate",
+			Severity:    SeverityWarning,
+			Run:         checkIdentical,
+		},
+	}
+}
+
+// ProtectedSpans returns the byte ranges of placeholders, rich text tags
+// and escaped line breaks of s, in the form of regexp index pairs.
+func ProtectedSpans(s string) [][]int {
+	return protectedPattern.FindAllStringIndex(s, -1)
+}
+
+// Placeholders returns placeholders of s in order of appearance.
+func Placeholders(s string) []string {
+	var out []string
+	for _, m := range placeholderPattern.FindAllString(s, -1) {
+		if m != "%%" {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// checkPlaceholders reports placeholders missing from or added to the translation.
+func checkPlaceholders(_ string, entry *poutil.Entry) []string {
+	missing, extra := countDiff(Placeholders(entry.MsgID), Placeholders(entry.MsgStr))
+
+	var msgs []string
+	for _, p := range missing {
+		msgs = append(msgs, fmt.Sprintf("missing placeholder %s", p))
+	}
+	for _, p := range extra {
+		msgs = append(msgs, fmt.Sprintf("unexpected placeholder %s", p))
+	}
+	return msgs
+}
+
+// tagCounts counts rich text tags by normalized form (<br/>, <color>, </color>).
+func tagCounts(s string) map[string]int {
+	counts := make(map[string]int)
+	for _, m := range tagPattern.FindAllStringSubmatch(s, -1) {
+		name := strings.ToLower(m[2])
+		switch {
+		case m[1] == "/":
+			name = "</" + name + ">"
+		case name == "br" || name == "image" || name == "img":
+			name = "<" + name + "/>"
+		default:
+			name = "<" + name + ">"
+		}
+		counts[name]++
+	}
+	return counts
+}
+
+// checkMarkup reports tags that appear a different number of times.
+func checkMarkup(_ string, entry *poutil.Entry) []string {
+	src, dst := tagCounts(entry.MsgID), tagCounts(entry.MsgStr)
+
+	tags := make([]string, 0, len(src)+len(dst))
+	for tag := range src {
+		tags = append(tags, tag)
+	}
+	for tag := range dst {
+		if _, ok := src[tag]; !ok {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	var msgs []string
+	for _, tag := range tags {
+		if src[tag] != dst[tag] {
+			msgs = append(msgs, fmt.Sprintf("tag %s appears %d times, original has %d", tag, dst[tag], src[tag]))
+		}
+	}
+	return msgs
+}
+
+// lineBreaks counts real and escaped (\n) line breaks.
+func lineBreaks(s string) int {
+	return strings.Count(s, "\n") + strings.Count(s, `\n`)
+}
+
+// checkNewlines reports a different number of line breaks.
+func checkNewlines(_ string, entry *poutil.Entry) []string {
+	src, dst := lineBreaks(entry.MsgID), lineBreaks(entry.MsgStr)
+	if src == dst {
+		return nil
+	}
+	return []string{fmt.Sprintf("%d line breaks, original has %d", dst, src)}
+}
+
+// checkWhitespace reports leading or trailing whitespace that differs.
+func checkWhitespace(_ string, entry *poutil.Entry) []string {
+	var msgs []string
+	if leadingSpace(entry.MsgID) != leadingSpace(entry.MsgStr) {
+		msgs = append(msgs, fmt.Sprintf("leading whitespace %q, original has %q",
+			leadingSpace(entry.MsgStr), leadingSpace(entry.MsgID)))
+	}
+	if trailingSpace(entry.MsgID) != trailingSpace(entry.MsgStr) {
+		msgs = append(msgs, fmt.Sprintf("trailing whitespace %q, original has %q",
+			trailingSpace(entry.MsgStr), trailingSpace(entry.MsgID)))
+	}
+	return msgs
+}
+
+// leadingSpace returns the whitespace prefix of s.
+func leadingSpace(s string) string {
+	return s[:len(s)-len(strings.TrimLeftFunc(s, unicode.IsSpace))]
+}
+
+// trailingSpace returns the whitespace suffix of s.
+func trailingSpace(s string) string {
+	return s[len(strings.TrimRightFunc(s, unicode.IsSpace)):]
+}
+
+// checkIdentical reports translations equal to the original that are not
+// marked notranslate.
+func checkIdentical(_ string, entry *poutil.Entry) []string {
+	if entry.MsgStr != entry.MsgID || entry.HasNoTranslate() {
+		return nil
+	}
+	return []string{"translation is identical to the original, mark the entry notranslate if intended"}
+}
+
+// countDiff returns items of want missing from got and items of got not in
+// want, counting repeated items.
+func countDiff(want, got []string) (missing, extra []string) {
+	counts := make(map[string]int)
+	for _, item := range got {
+		counts[item]++
+	}
+	for _, item := range want {
+		if counts[item] > 0 {
+			counts[item]--
+			continue
+		}
+		missing = append(missing, item)
+	}
+	for _, item := range got {
+		if counts[item] > 0 {
+			counts[item]--
+			extra = append(extra, item)
+		}
+	}
+	return missing, extra
+}
@@ -0,0 +1,55 @@
+package qa
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+func TestPlaceholders(t *testing.T) {
+	got := Placeholders("%1 of %2, 100%% {0} {name} %s %d {}")
+	want := []string{"%1", "%2", "{0}", "{name}", "%s", "%d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Placeholders() = %v, want %v", got, want)
+	}
+}
+
+func TestFormatChecks(t *testing.T) {
+	tests := []struct {
+		name   string
+		check  func(string, *poutil.Entry) []string
+		msgid  string
+		msgstr string
+		want   int
+	}{
+		{"placeholders ok", checkPlaceholders, "%1 / %2", "%2 из %1", 0},
+		{"placeholders missing", checkPlaceholders, "Ammo: %1 / %2", "Патроны: %1", 1},
+		{"placeholders extra", checkPlaceholders, "Ammo", "Патроны {0}", 1},
+		{"markup ok", checkMarkup, "<color name='red'>Hot</color><br/>", "<color name=\"red\">Жарко</color><br />", 0},
+		{"markup missing close", checkMarkup, "<color name='red'>Hot</color>", "<color name='red'>Жарко", 1},
+		{"markup image", checkMarkup, "<image set='dayz_gui' name='icon'/> Drink", "Пить", 1},
+		{"newlines ok", checkNewlines, "A\nB", "А\nБ", 0},
+		{"newlines escaped", checkNewlines, `A\nB`, "А Б", 1},
+		{"whitespace ok", checkWhitespace, " A ", " А ", 0},
+		{"whitespace both", checkWhitespace, "A: ", " А:", 2},
+		{"identical", checkIdentical, "DayZ", "DayZ", 1},
+		{"different", checkIdentical, "Yes", "Да", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.check("russian", &poutil.Entry{MsgID: tt.msgid, MsgStr: tt.msgstr})
+			if len(got) != tt.want {
+				t.Errorf("expected %d messages, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCheckIdenticalNoTranslate(t *testing.T) {
+	entry := &poutil.Entry{MsgID: "DayZ", MsgStr: "DayZ", Comments: []string{"# notranslate"}}
+	if got := checkIdentical("russian", entry); len(got) != 0 {
+		t.Errorf("expected notranslate entry to pass, got %v", got)
+	}
+}
@@ -0,0 +1,190 @@
+package qa
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/woozymasta/dayz-stringtable/internal/csvutil"
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// Glossary column names, every other column is a language.
+const (
+	GlossaryTermColumn          = "term"
+	GlossaryNoTranslateColumn   = "notranslate"
+	GlossaryCaseSensitiveColumn = "case"
+	GlossaryCommentColumn       = "comment"
+)
+
+// GlossaryTerm is a source term with its required translations.
+type GlossaryTerm struct {
+	pattern *regexp.Regexp
+
+	// Translations maps languages to accepted translations of the term,
+	// inflected forms are listed as separate variants.
+	Translations  map[string][]string
+	Term          string
+	NoTranslate   bool // The term must appear untranslated in every language
+	CaseSensitive bool // Match the term and its translations case-sensitively
+}
+
+// TermTranslation is a glossary term found in a text with the translation
+// required for a language.
+type TermTranslation struct {
+	Source string
+	Target string
+}
+
+// Glossary holds terminology that must be translated consistently.
+type Glossary struct {
+	Terms []*GlossaryTerm
+}
+
+// LoadGlossary reads a glossary CSV. The first column is "term", optional
+// "notranslate" and "case" columns hold yes/no values, other columns are
+// languages with translations of the term (variants separated by "|").
+func LoadGlossary(path string) (*Glossary, error) {
+	rows, err := csvutil.LoadCSV(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load glossary: %w", err)
+	}
+	if len(rows) == 0 || !strings.EqualFold(strings.TrimSpace(rows[0][0]), GlossaryTermColumn) {
+		return nil, fmt.Errorf("glossary %s must start with a '%s' column", path, GlossaryTermColumn)
+	}
+
+	header := rows[0]
+	noTranslateCol, caseCol := -1, -1
+	langCols := make(map[int]string)
+	for i, name := range header[1:] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "", GlossaryCommentColumn:
+		case GlossaryNoTranslateColumn:
+			noTranslateCol = i + 1
+		case GlossaryCaseSensitiveColumn, "case-sensitive":
+			caseCol = i + 1
+		default:
+			langCols[i+1] = name
+		}
+	}
+
+	g := &Glossary{}
+	for _, row := range rows[1:] {
+		term := strings.TrimSpace(row[0])
+		if term == "" {
+			continue
+		}
+
+		t := &GlossaryTerm{
+			Term:          term,
+			NoTranslate:   noTranslateCol >= 0 && noTranslateCol < len(row) && isYes(row[noTranslateCol]),
+			CaseSensitive: caseCol >= 0 && caseCol < len(row) && isYes(row[caseCol]),
+			Translations:  make(map[string][]string),
+		}
+		for col, lang := range langCols {
+			if col >= len(row) {
+				continue
+			}
+			for _, variant := range strings.Split(row[col], "|") {
+				if variant = strings.TrimSpace(variant); variant != "" {
+					t.Translations[lang] = append(t.Translations[lang], variant)
+				}
+			}
+		}
+
+		// Unlike \b these boundaries work for non-ASCII letters and for
+		// terms that start or end with punctuation, like "C++"
+		pattern := `(?:^|[^\p{L}\p{N}_])` + regexp.QuoteMeta(term) + `(?:$|[^\p{L}\p{N}_])`
+		if !t.CaseSensitive {
+			pattern = `(?i)` + pattern
+		}
+		t.pattern = regexp.MustCompile(pattern)
+		g.Terms = append(g.Terms, t)
+	}
+	return g, nil
+}
+
+// isYes reports whether a glossary option cell is set.
+func isYes(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "x", "y", "yes", "true":
+		return true
+	}
+	return false
+}
+
+// Targets returns accepted translations of the term for lang. A
+// do-not-translate term is its own translation.
+func (t *GlossaryTerm) Targets(lang string) []string {
+	if t.NoTranslate {
+		return []string{t.Term}
+	}
+	return t.Translations[lang]
+}
+
+// Matches reports whether the term appears in text.
+func (t *GlossaryTerm) Matches(text string) bool {
+	return t.pattern.MatchString(text)
+}
+
+// hasTarget reports whether text contains any accepted translation for lang.
+func (t *GlossaryTerm) hasTarget(lang, text string) bool {
+	for _, target := range t.Targets(lang) {
+		if t.CaseSensitive {
+			if strings.Contains(text, target) {
+				return true
+			}
+		} else if strings.Contains(strings.ToLower(text), strings.ToLower(target)) {
+			return true
+		}
+	}
+	return false
+}
+
+// TermsFor returns glossary terms found in text with the first accepted
+// translation for lang. Terms without a translation for lang are skipped.
+func (g *Glossary) TermsFor(lang, text string) []TermTranslation {
+	if g == nil {
+		return nil
+	}
+
+	var out []TermTranslation
+	for _, t := range g.Terms {
+		targets := t.Targets(lang)
+		if len(targets) == 0 || !t.Matches(text) {
+			continue
+		}
+		out = append(out, TermTranslation{Source: t.Term, Target: targets[0]})
+	}
+	return out
+}
+
+// GlossaryCheck returns the check reporting glossary terms of msgid whose
+// required translation is missing from msgstr.
+func GlossaryCheck(g *Glossary) Check {
+	return Check{
+		Name:        "glossary",
+		Description: "Glossary terms of the original use their glossary translation",
+		Severity:    SeverityWarning,
+		Run: func(lang string, entry *poutil.Entry) []string {
+			if g == nil || entry.HasNoTranslate() {
+				return nil
+			}
+
+			var msgs []string
+			for _, t := range g.Terms {
+				targets := t.Targets(lang)
+				if len(targets) == 0 || !t.Matches(entry.MsgID) || t.hasTarget(lang, entry.MsgStr) {
+					continue
+				}
+				if t.NoTranslate {
+					msgs = append(msgs, fmt.Sprintf("term %q must stay untranslated", t.Term))
+				} else {
+					msgs = append(msgs, fmt.Sprintf("term %q should be translated as %q", t.Term, strings.Join(targets, `" or "`)))
+				}
+			}
+			return msgs
+		},
+	}
+}
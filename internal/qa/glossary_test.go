@@ -0,0 +1,118 @@
+package qa
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// writeGlossary writes a glossary CSV used by the tests.
+func writeGlossary(t *testing.T) string {
+	t.Helper()
+	content := `"term","notranslate","case","comment","russian","german",
+"Bandage","","","","Бинт|Бинта","Verband",
+"Blood Bag","","","","Пакет крови","",
+"DayZ","yes","yes","game title","","",
+`
+	path := filepath.Join(t.TempDir(), "glossary.csv")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write glossary: %v", err)
+	}
+	return path
+}
+
+func TestLoadGlossary(t *testing.T) {
+	g, err := LoadGlossary(writeGlossary(t))
+	if err != nil {
+		t.Fatalf("LoadGlossary failed: %v", err)
+	}
+	if len(g.Terms) != 3 {
+		t.Fatalf("expected 3 terms, got %d", len(g.Terms))
+	}
+
+	bandage := g.Terms[0]
+	if !reflect.DeepEqual(bandage.Translations["russian"], []string{"Бинт", "Бинта"}) {
+		t.Errorf("unexpected russian translations: %v", bandage.Translations["russian"])
+	}
+	if _, ok := bandage.Translations["comment"]; ok {
+		t.Error("comment column must not be a language")
+	}
+	if dayz := g.Terms[2]; !dayz.NoTranslate || !dayz.CaseSensitive {
+		t.Errorf("expected DayZ to be notranslate and case-sensitive: %+v", dayz)
+	}
+
+	got := g.TermsFor("german", "Use a bandage or a blood bag in DayZ")
+	want := []TermTranslation{{"Bandage", "Verband"}, {"DayZ", "DayZ"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TermsFor() = %v, want %v", got, want)
+	}
+}
+
+func TestGlossaryTerm_Matches(t *testing.T) {
+	content := `"term","case",
+"Аптечка","",
+"C++","yes",
+"M4-A1.","yes",
+`
+	path := filepath.Join(t.TempDir(), "glossary.csv")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write glossary: %v", err)
+	}
+	g, err := LoadGlossary(path)
+	if err != nil {
+		t.Fatalf("LoadGlossary failed: %v", err)
+	}
+	medkit, cpp, m4 := g.Terms[0], g.Terms[1], g.Terms[2]
+
+	tests := []struct {
+		term *GlossaryTerm
+		text string
+		want bool
+	}{
+		{medkit, "Нужна аптечка.", true},
+		{medkit, "Аптечка", true},
+		{medkit, "Аптечками", false},
+		{medkit, "Минаптечка", false},
+		{cpp, "Written in C++", true},
+		{cpp, "C++, Go", true},
+		{cpp, "C++x", false},
+		{m4, "Found M4-A1.", true},
+		{m4, "M4-A1. Rifle", true},
+		{m4, "M4-A1.5", false},
+	}
+	for _, tt := range tests {
+		if got := tt.term.Matches(tt.text); got != tt.want {
+			t.Errorf("%q.Matches(%q) = %v, want %v", tt.term.Term, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestGlossaryCheck(t *testing.T) {
+	g, err := LoadGlossary(writeGlossary(t))
+	if err != nil {
+		t.Fatalf("LoadGlossary failed: %v", err)
+	}
+	checker := NewChecker(GlossaryCheck(g))
+
+	tests := []struct {
+		msgid, msgstr string
+		want          int
+	}{
+		{"Apply bandage", "Наложить бинт", 0},
+		{"No bandage left", "Перевязки нет", 1},
+		{"Bandages", "Перевязки", 0},
+		{"Blood Bag", "Пакет крови", 0},
+		{"Welcome to DayZ", "Добро пожаловать в Дейзи", 1},
+		{"Welcome to DayZ", "Добро пожаловать в DayZ", 0},
+		{"Welcome to dayz", "Добро пожаловать", 0},
+	}
+	for _, tt := range tests {
+		entry := &poutil.Entry{Context: "STR_X", MsgID: tt.msgid, MsgStr: tt.msgstr}
+		if got := checker.CheckEntry("russian", entry); len(got) != tt.want {
+			t.Errorf("%q -> %q: expected %d findings, got %+v", tt.msgid, tt.msgstr, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package qa
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/woozymasta/dayz-stringtable/internal/font"
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// MissingGlyphs returns distinct characters of text, in order of
+// appearance, that none of the fonts can render. Rich text tags, escaped
+// line breaks and control characters are skipped.
+func MissingGlyphs(fonts []*font.Font, text string) []rune {
+	if len(fonts) == 0 {
+		return nil
+	}
+	text = tagPattern.ReplaceAllString(text, "")
+	text = strings.ReplaceAll(text, `\n`, "")
+
+	var missing []rune
+	seen := make(map[rune]bool)
+	for _, r := range text {
+		if seen[r] || unicode.IsControl(r) {
+			continue
+		}
+		seen[r] = true
+
+		covered := false
+		for _, f := range fonts {
+			if f.Has(r) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			missing = append(missing, r)
+		}
+	}
+	return missing
+}
+
+// GlyphCheck returns the check reporting characters of msgstr and msgid
+// that fonts can't render. A character is covered if any font has it.
+// The check does nothing without fonts.
+func GlyphCheck(fonts []*font.Font) Check {
+	messages := func(text string) []string {
+		missing := MissingGlyphs(fonts, text)
+		if len(missing) == 0 {
+			return nil
+		}
+		chars := make([]string, 0, len(missing))
+		for _, r := range missing {
+			chars = append(chars, fmt.Sprintf("%q (U+%04X)", r, r))
+		}
+		return []string{"no glyph in font for " + strings.Join(chars, ", ")}
+	}
+
+	return Check{
+		Name:        "glyphs",
+		Description: "Characters of the translation (and of the original) are covered by the --font fonts",
+		Severity:    SeverityError,
+		Run:         func(_ string, entry *poutil.Entry) []string { return messages(entry.MsgStr) },
+		RunOriginal: func(entry *poutil.Entry) []string { return messages(entry.MsgID) },
+	}
+}
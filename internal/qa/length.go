@@ -0,0 +1,118 @@
+package qa
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// MaxLengthFlag is the Weblate style entry flag with the maximum
+// translation length ("#, max-length:24").
+const MaxLengthFlag = "max-length"
+
+// LengthLimits configures the length checks. Lengths are counted in runes.
+type LengthLimits struct {
+	// PrefixMaxLength maps key prefixes to the maximum translation length,
+	// used for entries without a max-length flag. The longest matching
+	// prefix wins.
+	PrefixMaxLength map[string]int
+
+	// MaxRatio is the maximum length of the translation relative to the
+	// original (1.5 allows 50% longer translations), 0 disables the check.
+	MaxRatio float64
+
+	// RatioMinLength skips the ratio check for originals shorter than this,
+	// short strings like "OK" naturally expand a lot.
+	RatioMinLength int
+}
+
+// LengthChecks returns the absolute length and expansion ratio checks.
+func LengthChecks(limits *LengthLimits) []Check {
+	return []Check{
+		{
+			Name:        "maxlen",
+			Description: "The translation fits the max-length flag or the limit of its key prefix",
+			Severity:    SeverityError,
+			Run: func(_ string, entry *poutil.Entry) []string {
+				if msg, ok := limits.checkMaxLength(entry); !ok {
+					return []string{msg}
+				}
+				return nil
+			},
+		},
+		{
+			Name:        "expansion",
+			Description: "The translation is not much longer than the original",
+			Severity:    SeverityWarning,
+			Run: func(_ string, entry *poutil.Entry) []string {
+				if msg, ok := limits.checkRatio(entry); !ok {
+					return []string{msg}
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// MaxLength returns the maximum translation length of an entry from its
+// max-length flag or the longest matching key prefix.
+func (l *LengthLimits) MaxLength(entry *poutil.Entry) (int, bool) {
+	if value, ok := entry.FlagValue(MaxLengthFlag); ok {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			return n, true
+		}
+	}
+	if l == nil {
+		return 0, false
+	}
+
+	best, limit := -1, 0
+	for prefix, n := range l.PrefixMaxLength {
+		if strings.HasPrefix(entry.Context, prefix) && len(prefix) > best {
+			best, limit = len(prefix), n
+		}
+	}
+	return limit, best >= 0
+}
+
+// Exceeds reports whether a translated entry breaks any length limit.
+func (l *LengthLimits) Exceeds(entry *poutil.Entry) bool {
+	if entry.MsgStr == "" {
+		return false
+	}
+	_, lengthOK := l.checkMaxLength(entry)
+	_, ratioOK := l.checkRatio(entry)
+	return !lengthOK || !ratioOK
+}
+
+// checkMaxLength returns a message and false if the translation is too long.
+func (l *LengthLimits) checkMaxLength(entry *poutil.Entry) (string, bool) {
+	limit, ok := l.MaxLength(entry)
+	if !ok {
+		return "", true
+	}
+	if n := utf8.RuneCountInString(entry.MsgStr); n > limit {
+		return fmt.Sprintf("%d characters, limit is %d", n, limit), false
+	}
+	return "", true
+}
+
+// checkRatio returns a message and false if the translation expands the
+// original more than MaxRatio.
+func (l *LengthLimits) checkRatio(entry *poutil.Entry) (string, bool) {
+	if l == nil || l.MaxRatio <= 0 {
+		return "", true
+	}
+	src := utf8.RuneCountInString(entry.MsgID)
+	if src == 0 || src < l.RatioMinLength {
+		return "", true
+	}
+	ratio := float64(utf8.RuneCountInString(entry.MsgStr)) / float64(src)
+	if ratio > l.MaxRatio {
+		return fmt.Sprintf("%.2fx the original length, limit is %.2fx", ratio, l.MaxRatio), false
+	}
+	return "", true
+}
@@ -0,0 +1,58 @@
+package qa
+
+import (
+	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+func TestLengthLimits_MaxLength(t *testing.T) {
+	limits := &LengthLimits{PrefixMaxLength: map[string]int{"STR_": 40, "STR_HUD_": 12}}
+
+	tests := []struct {
+		entry *poutil.Entry
+		want  int
+		ok    bool
+	}{
+		{&poutil.Entry{Context: "STR_HUD_Ammo"}, 12, true},
+		{&poutil.Entry{Context: "STR_Menu"}, 40, true},
+		{&poutil.Entry{Context: "STR_HUD_Ammo", Comments: []string{"#, max-length:8"}}, 8, true},
+		{&poutil.Entry{Context: "UI_Menu"}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := limits.MaxLength(tt.entry)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("MaxLength(%s) = %d, %v, want %d, %v", tt.entry.Context, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLengthChecks(t *testing.T) {
+	limits := &LengthLimits{MaxRatio: 1.5, RatioMinLength: 5}
+	checker := NewChecker(LengthChecks(limits)...)
+
+	po := poutil.NewFile()
+	po.Entries = append(po.Entries,
+		&poutil.Entry{Context: "STR_A", MsgID: "Hot", MsgStr: "Жарковато", Comments: []string{"#, max-length:5"}},
+		&poutil.Entry{Context: "STR_B", MsgID: "Drink water", MsgStr: "Выпейте воды прямо сейчас"},
+		&poutil.Entry{Context: "STR_C", MsgID: "OK", MsgStr: "Хорошо"},
+		&poutil.Entry{Context: "STR_D", MsgID: "Hot", MsgStr: "Жара", Comments: []string{"#, max-length:5"}},
+	)
+
+	findings := checker.CheckFile("russian", po)
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", findings)
+	}
+	if findings[0].Key != "STR_A" || findings[0].Check != "maxlen" || findings[0].Severity != SeverityError {
+		t.Errorf("unexpected finding: %+v", findings[0])
+	}
+	if findings[1].Key != "STR_B" || findings[1].Check != "expansion" || findings[1].Severity != SeverityWarning {
+		t.Errorf("unexpected finding: %+v", findings[1])
+	}
+
+	for i, want := range []bool{true, true, false, false} {
+		if got := limits.Exceeds(po.Entries[i]); got != want {
+			t.Errorf("Exceeds(%s) = %v, want %v", po.Entries[i].Context, got, want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Package qa implements quality checks of translated PO entries against
+// their original text: placeholders, markup, whitespace and similar.
+package qa
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// Severity is the level of a finding.
+type Severity string
+
+// Severity levels, SeverityOff disables a check.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityOff     Severity = "off"
+)
+
+// SuppressFlag is the entry flag that disables all checks for an entry,
+// a single check is disabled with SuppressFlag + "-" + check name
+// (e.g. "#, no-check-identical").
+const SuppressFlag = "no-check"
+
+// ParseSeverity parses a severity name.
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(strings.ToLower(strings.TrimSpace(s))); sev {
+	case SeverityError, SeverityWarning, SeverityOff:
+		return sev, nil
+	}
+	return "", fmt.Errorf("unknown severity '%s', expected error, warning or off", s)
+}
+
+// Finding is a single problem found in a translated entry.
+// Language, File and Line are filled by the caller that knows where
+// the entry comes from.
+type Finding struct {
+	Check    string   `json:"check"`              // Check name (e.g. "placeholders")
+	Severity Severity `json:"severity"`           // Severity of the check
+	Language string   `json:"language,omitempty"` // Language of the PO file
+	Key      string   `json:"key"`                // Entry key (msgctxt)
+	Message  string   `json:"message"`            // Human readable description
+	File     string   `json:"file,omitempty"`     // PO file path
+	Line     int      `json:"line,omitempty"`     // Line of msgctxt in the PO file
+}
+
+// Check is a single named check of a translated entry.
+// Run returns one message per problem found. RunOriginal, if set, checks
+// the original text of an entry once for all languages.
+type Check struct {
+	Run         func(lang string, entry *poutil.Entry) []string
+	RunOriginal func(entry *poutil.Entry) []string
+	Name        string
+	Description string
+	Severity    Severity // Default severity
+}
+
+// Checker runs a set of checks with configurable severities.
+type Checker struct {
+	severity map[string]Severity
+	Checks   []Check
+}
+
+// NewChecker returns a checker with the given checks at their default severity.
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{Checks: checks, severity: make(map[string]Severity)}
+}
+
+// SetSeverity overrides the severity of a check by name.
+func (c *Checker) SetSeverity(name string, sev Severity) error {
+	for _, check := range c.Checks {
+		if check.Name == name {
+			c.severity[name] = sev
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown check '%s', expected one of: %s", name, strings.Join(c.Names(), ", "))
+}
+
+// Severity returns the effective severity of a check.
+func (c *Checker) Severity(check Check) Severity {
+	if sev, ok := c.severity[check.Name]; ok {
+		return sev
+	}
+	return check.Severity
+}
+
+// Names returns the sorted names of all checks.
+func (c *Checker) Names() []string {
+	names := make([]string, 0, len(c.Checks))
+	for _, check := range c.Checks {
+		names = append(names, check.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckEntry runs all enabled checks on a translated entry.
+// Entries without msgstr or suppressed with SuppressFlag are skipped.
+func (c *Checker) CheckEntry(lang string, entry *poutil.Entry) []Finding {
+	if entry.MsgStr == "" || entry.HasFlag(SuppressFlag) {
+		return nil
+	}
+
+	var findings []Finding
+	for _, check := range c.Checks {
+		sev := c.Severity(check)
+		if sev == SeverityOff || entry.HasFlag(SuppressFlag+"-"+check.Name) {
+			continue
+		}
+		for _, msg := range check.Run(lang, entry) {
+			findings = append(findings, Finding{
+				Check:    check.Name,
+				Severity: sev,
+				Language: lang,
+				Key:      entry.Context,
+				Message:  msg,
+			})
+		}
+	}
+	return findings
+}
+
+// CheckFile runs all enabled checks on every entry of a PO file.
+func (c *Checker) CheckFile(lang string, po *poutil.File) []Finding {
+	var findings []Finding
+	for _, entry := range po.Entries {
+		findings = append(findings, c.CheckEntry(lang, entry)...)
+	}
+	return findings
+}
+
+// CheckOriginals runs enabled checks of original texts on every entry of
+// a PO file. Findings have OriginalLanguage as language, entries
+// suppressed with SuppressFlag are skipped.
+func (c *Checker) CheckOriginals(po *poutil.File) []Finding {
+	var findings []Finding
+	for _, check := range c.Checks {
+		sev := c.Severity(check)
+		if check.RunOriginal == nil || sev == SeverityOff {
+			continue
+		}
+		for _, entry := range po.Entries {
+			if entry.HasFlag(SuppressFlag) || entry.HasFlag(SuppressFlag+"-"+check.Name) {
+				continue
+			}
+			for _, msg := range check.RunOriginal(entry) {
+				findings = append(findings, Finding{
+					Check:    check.Name,
+					Severity: sev,
+					Language: OriginalLanguage,
+					Key:      entry.Context,
+					Message:  msg,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// Count returns the number of errors and warnings in findings.
+func Count(findings []Finding) (errors, warnings int) {
+	for _, f := range findings {
+		switch f.Severity {
+		case SeverityError:
+			errors++
+		case SeverityWarning:
+			warnings++
+		}
+	}
+	return errors, warnings
+}
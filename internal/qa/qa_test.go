@@ -0,0 +1,52 @@
+package qa
+
+import (
+	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+func TestChecker_SeverityAndSuppression(t *testing.T) {
+	checker := NewChecker(FormatChecks()...)
+	po := poutil.NewFile()
+	po.Entries = append(po.Entries,
+		&poutil.Entry{Context: "STR_A", MsgID: "%1 / %2", MsgStr: "%1"},
+		&poutil.Entry{Context: "STR_B", MsgID: "DayZ", MsgStr: "DayZ"},
+		&poutil.Entry{Context: "STR_C", MsgID: "OK", MsgStr: "OK", Comments: []string{"#, no-check-identical"}},
+		&poutil.Entry{Context: "STR_D", MsgID: "%1", MsgStr: "", Comments: []string{}},
+		&poutil.Entry{Context: "STR_E", MsgID: "%1", MsgStr: "x", Comments: []string{"#, no-check"}},
+	)
+
+	findings := checker.CheckFile("russian", po)
+	errors, warnings := Count(findings)
+	if errors != 1 || warnings != 1 {
+		t.Fatalf("expected 1 error and 1 warning, got %d and %d: %+v", errors, warnings, findings)
+	}
+	if findings[0].Key != "STR_A" || findings[0].Check != "placeholders" || findings[0].Language != "russian" {
+		t.Errorf("unexpected first finding: %+v", findings[0])
+	}
+
+	if err := checker.SetSeverity("placeholders", SeverityWarning); err != nil {
+		t.Fatalf("SetSeverity failed: %v", err)
+	}
+	if err := checker.SetSeverity("identical", SeverityOff); err != nil {
+		t.Fatalf("SetSeverity failed: %v", err)
+	}
+	errors, warnings = Count(checker.CheckFile("russian", po))
+	if errors != 0 || warnings != 1 {
+		t.Errorf("expected 0 errors and 1 warning after overrides, got %d and %d", errors, warnings)
+	}
+
+	if err := checker.SetSeverity("unknown", SeverityOff); err == nil {
+		t.Error("expected error for unknown check")
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	if sev, err := ParseSeverity(" Warning "); err != nil || sev != SeverityWarning {
+		t.Errorf("ParseSeverity() = %q, %v", sev, err)
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+}
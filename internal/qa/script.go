@@ -0,0 +1,254 @@
+package qa
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// LanguageScripts maps DayZ languages to the Unicode scripts (names of
+// unicode.Scripts) their translations are written in.
+var LanguageScripts = map[string][]string{
+	"english":        {"Latin"},
+	"czech":          {"Latin"},
+	"german":         {"Latin"},
+	"russian":        {"Cyrillic"},
+	"polish":         {"Latin"},
+	"hungarian":      {"Latin"},
+	"italian":        {"Latin"},
+	"spanish":        {"Latin"},
+	"french":         {"Latin"},
+	"chinese":        {"Han"},
+	"japanese":       {"Han", "Hiragana", "Katakana"},
+	"portuguese":     {"Latin"},
+	"chinesesimp":    {"Han"},
+	OriginalLanguage: {"Latin"},
+}
+
+// scriptMinLetters is the minimum number of letters for the script check
+// to judge which script a translation is mostly written in.
+const scriptMinLetters = 3
+
+// invisibleNames names characters reported by the invisible check.
+var invisibleNames = map[rune]string{
+	'\u00a0': "no-break space",
+	'\u00ad': "soft hyphen",
+	'\u200b': "zero width space",
+	'\u200c': "zero width non-joiner",
+	'\u200d': "zero width joiner",
+	'\u200e': "left-to-right mark",
+	'\u200f': "right-to-left mark",
+	'\u2007': "figure space",
+	'\u2060': "word joiner",
+	'\u202f': "narrow no-break space",
+	'\ufeff': "byte order mark",
+	'\ufffd': "replacement character",
+}
+
+// CharsetChecks returns the checks of scripts, invisible characters and
+// Unicode normalization.
+func CharsetChecks() []Check {
+	return []Check{
+		{
+			Name:        "script",
+			Description: "The translation is written in the scripts of its language, without mixed-script words",
+			Severity:    SeverityWarning,
+			Run: func(lang string, entry *poutil.Entry) []string {
+				if entry.HasNoTranslate() {
+					return nil
+				}
+				return checkScript(lang, entry.MsgStr, entry.MsgID)
+			},
+			RunOriginal: func(entry *poutil.Entry) []string { return checkScript(OriginalLanguage, entry.MsgID, "") },
+		},
+		{
+			Name:        "invisible",
+			Description: "No invisible, control or non-breaking space characters that DayZ fonts render as boxes",
+			Severity:    SeverityError,
+			Run:         func(_ string, entry *poutil.Entry) []string { return checkInvisible(entry.MsgStr) },
+			RunOriginal: func(entry *poutil.Entry) []string { return checkInvisible(entry.MsgID) },
+		},
+		{
+			Name:        "nfc",
+			Description: "The text is in Unicode NFC form (precomposed characters)",
+			Severity:    SeverityWarning,
+			Run:         func(_ string, entry *poutil.Entry) []string { return checkNFC(entry.MsgStr) },
+			RunOriginal: func(entry *poutil.Entry) []string { return checkNFC(entry.MsgID) },
+		},
+	}
+}
+
+// scriptOf returns the name of the script of a letter, "" if it is not
+// in any script table.
+func scriptOf(r rune) string {
+	for _, name := range []string{"Latin", "Cyrillic", "Han", "Hiragana", "Katakana", "Greek"} {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	for name, table := range unicode.Scripts {
+		if name != "Common" && name != "Inherited" && unicode.Is(table, r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// checkScript reports text mostly written in a script not used by lang,
+// and words mixing an expected script with another one (Latin "o" in a
+// Cyrillic word). Words that also appear in the original, like names kept
+// in English, are not counted.
+func checkScript(lang, text, original string) []string {
+	expected := LanguageScripts[lang]
+	if len(expected) == 0 {
+		return nil
+	}
+	isExpected := func(script string) bool { return containsString(expected, script) }
+
+	kept := make(map[string]bool)
+	for _, word := range Words(original) {
+		kept[strings.ToLower(word)] = true
+	}
+
+	var msgs []string
+	counts := make(map[string]int)
+	good, total := 0, 0
+	for _, word := range Words(text) {
+		if kept[strings.ToLower(word)] {
+			continue
+		}
+		mixed := ""
+		for _, part := range strings.Split(word, "-") {
+			if foreign := countScripts(part, isExpected, counts, &good, &total); foreign != "" && mixed == "" {
+				mixed = foreign
+			}
+		}
+		if mixed != "" {
+			msgs = append(msgs, fmt.Sprintf("word %q mixes %s with %s letters", word, strings.Join(expected, "/"), mixed))
+		}
+	}
+
+	if total >= scriptMinLetters && good*2 < total {
+		scripts := make([]string, 0, len(counts))
+		for script := range counts {
+			if !isExpected(script) {
+				scripts = append(scripts, script)
+			}
+		}
+		sort.Slice(scripts, func(i, j int) bool {
+			if counts[scripts[i]] != counts[scripts[j]] {
+				return counts[scripts[i]] > counts[scripts[j]]
+			}
+			return scripts[i] < scripts[j]
+		})
+		msgs = append([]string{fmt.Sprintf("mostly %s letters, expected %s",
+			scripts[0], strings.Join(expected, "/"))}, msgs...)
+	}
+	return msgs
+}
+
+// countScripts counts letters of a word by script into counts, good and
+// total, and returns the foreign scripts of a word that also has letters
+// of an expected script, "" otherwise.
+func countScripts(word string, isExpected func(string) bool, counts map[string]int, good, total *int) string {
+	var hasExpected bool
+	var foreign []string
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		script := scriptOf(r)
+		if script == "" {
+			continue
+		}
+		*total++
+		counts[script]++
+		if isExpected(script) {
+			*good++
+			hasExpected = true
+		} else if !containsString(foreign, script) {
+			foreign = append(foreign, script)
+		}
+	}
+	if hasExpected && len(foreign) > 0 {
+		return strings.Join(foreign, "/")
+	}
+	return ""
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// isInvisible reports whether r is a format, control (except line break)
+// or private use character, or a space other than the regular and
+// ideographic ones.
+func isInvisible(r rune) bool {
+	switch {
+	case r == '\n' || r == ' ' || r == '\u3000':
+		return false
+	case invisibleNames[r] != "":
+		return true
+	}
+	return unicode.In(r, unicode.Cc, unicode.Cf, unicode.Co, unicode.Zs, unicode.Zl, unicode.Zp)
+}
+
+// checkInvisible reports each distinct invisible character of s.
+func checkInvisible(s string) []string {
+	var msgs []string
+	seen := make(map[rune]bool)
+	for _, r := range s {
+		if seen[r] || !isInvisible(r) {
+			continue
+		}
+		seen[r] = true
+
+		name := invisibleNames[r]
+		if name == "" {
+			name = "control or format character"
+			if unicode.In(r, unicode.Zs, unicode.Zl, unicode.Zp) {
+				name = "special space"
+			}
+		}
+		msgs = append(msgs, fmt.Sprintf("invisible character U+%04X (%s)", r, name))
+	}
+	return msgs
+}
+
+// checkNFC reports text that changes under NFC normalization.
+func checkNFC(s string) []string {
+	if norm.NFC.IsNormalString(s) {
+		return nil
+	}
+	return []string{"text is not in Unicode NFC form, decomposed characters may render as boxes"}
+}
+
+// Fix applies safe normalizations to a translation: NFC normalization,
+// special spaces replaced with a regular space and other invisible
+// characters removed. Line breaks are kept.
+func Fix(s string) string {
+	s = norm.NFC.String(s)
+	return strings.Map(func(r rune) rune {
+		if !isInvisible(r) {
+			return r
+		}
+		switch {
+		case unicode.In(r, unicode.Zs) || r == '\t':
+			return ' '
+		case unicode.In(r, unicode.Zl, unicode.Zp):
+			return '\n'
+		}
+		return -1
+	}, s)
+}
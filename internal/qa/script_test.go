@@ -0,0 +1,55 @@
+package qa
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckScript(t *testing.T) {
+	tests := []struct {
+		lang, text, original string
+		want                 []string
+	}{
+		{"russian", "Открыть дверь", "Open door", nil},
+		{"russian", "Открыть AKM", "Open AKM", nil},
+		{"russian", "Open the door", "Open door", []string{"mostly Latin letters, expected Cyrillic"}},
+		// Latin "o" in a Cyrillic word
+		{"russian", "Зaкрыть", "Close", []string{`word "Зaкрыть" mixes Cyrillic with Latin letters`}},
+		{"polish", "Закрыть drzwi", "Close door", []string{"mostly Cyrillic letters, expected Latin"}},
+		{"japanese", "ドアを開ける", "Open door", nil},
+		{"klingon", "Open", "Open", nil},
+	}
+	for _, tt := range tests {
+		if got := checkScript(tt.lang, tt.text, tt.original); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("checkScript(%q, %q) = %q, want %q", tt.lang, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCheckInvisible(t *testing.T) {
+	got := checkInvisible("Drop\u00a0all\u200b\u200b\nnow")
+	want := []string{
+		"invisible character U+00A0 (no-break space)",
+		"invisible character U+200B (zero width space)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkInvisible = %q, want %q", got, want)
+	}
+	if got := checkInvisible("Line\nbreak　全角"); got != nil {
+		t.Errorf("unexpected findings %q", got)
+	}
+}
+
+func TestFix(t *testing.T) {
+	// "e" followed by a combining acute accent, NBSP and zero width space
+	in := "Cafe\u0301\u00a0ouvert\u200b\n"
+	if got, want := Fix(in), "Café ouvert\n"; got != want {
+		t.Errorf("Fix = %q, want %q", got, want)
+	}
+	if checkNFC(in) == nil {
+		t.Errorf("decomposed text should be reported")
+	}
+	if checkNFC(Fix(in)) != nil {
+		t.Errorf("fixed text should be NFC")
+	}
+}
@@ -0,0 +1,164 @@
+package qa
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/woozymasta/dayz-stringtable/internal/hunspell"
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+// OriginalLanguage is the language name of msgid texts, as the "original"
+// CSV column.
+const OriginalLanguage = "original"
+
+// Speller checks spelling with a Hunspell dictionary per language.
+type Speller struct {
+	// Dictionaries maps languages to dictionaries, OriginalLanguage is used
+	// for msgid texts.
+	Dictionaries map[string]*hunspell.Dictionary
+
+	// Ignore holds lower case words accepted in every language, such as
+	// game jargon and item names.
+	Ignore map[string]bool
+}
+
+// LoadWordList reads a word list with one word per line into a set of
+// lower case words. Empty lines and lines starting with # are skipped.
+func LoadWordList(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open word list: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	words := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words[strings.ToLower(line)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read word list %s: %w", path, err)
+	}
+	return words, nil
+}
+
+// Words splits text into words, skipping placeholders, rich text tags and
+// escaped line breaks. Hyphens and apostrophes inside a word are kept
+// ("M4-A1", "don't").
+func Words(text string) []string {
+	text = placeholderPattern.ReplaceAllString(text, " ")
+	text = tagPattern.ReplaceAllString(text, " ")
+	text = strings.NewReplacer(`\n`, " ", `\t`, " ", "’", "'").Replace(text)
+
+	var (
+		words []string
+		word  []rune
+	)
+	flush := func() {
+		// Joiners are only kept between letters or digits
+		for len(word) > 0 && isJoiner(word[len(word)-1]) {
+			word = word[:len(word)-1]
+		}
+		if len(word) > 0 {
+			words = append(words, string(word))
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			word = append(word, r)
+		case isJoiner(r) && len(word) > 0:
+			word = append(word, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// isJoiner reports whether r may join parts of a word.
+func isJoiner(r rune) bool {
+	return r == '-' || r == '\''
+}
+
+// Misspelled returns distinct misspelled words of text in order of
+// appearance. Returns nil if there is no dictionary for lang.
+func (s *Speller) Misspelled(lang, text string) []string {
+	if s == nil {
+		return nil
+	}
+	dict := s.Dictionaries[lang]
+	if dict == nil {
+		return nil
+	}
+
+	var out []string
+	seen := make(map[string]bool)
+	for _, word := range Words(text) {
+		if seen[word] || s.accepts(dict, word) {
+			continue
+		}
+		seen[word] = true
+		out = append(out, word)
+	}
+	return out
+}
+
+// accepts reports whether word is ignored or spelled correctly, as a
+// whole or in all of its hyphenated parts. Words with digits such as
+// model names (M4-A1) are always accepted.
+func (s *Speller) accepts(dict *hunspell.Dictionary, word string) bool {
+	if s.Ignore[strings.ToLower(word)] || strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+		return true
+	}
+	if dict.Spell(word) {
+		return true
+	}
+	if !strings.Contains(word, "-") {
+		return false
+	}
+	for _, part := range strings.Split(word, "-") {
+		if part != "" && !s.Ignore[strings.ToLower(part)] && !dict.Spell(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// SpellCheck returns the check reporting misspelled words of msgstr, and
+// of msgid when a dictionary for OriginalLanguage is set. The check does
+// nothing for languages without a dictionary or with a nil speller.
+func SpellCheck(s *Speller) Check {
+	messages := func(words []string) []string {
+		var msgs []string
+		for _, word := range words {
+			msgs = append(msgs, fmt.Sprintf("unknown word %q", word))
+		}
+		return msgs
+	}
+
+	return Check{
+		Name:        "spelling",
+		Description: "Words of the translation (and of the original) are in the Hunspell dictionary or ignore list",
+		Severity:    SeverityWarning,
+		Run: func(lang string, entry *poutil.Entry) []string {
+			if entry.HasNoTranslate() {
+				return nil
+			}
+			return messages(s.Misspelled(lang, entry.MsgStr))
+		},
+		RunOriginal: func(entry *poutil.Entry) []string {
+			return messages(s.Misspelled(OriginalLanguage, entry.MsgID))
+		},
+	}
+}
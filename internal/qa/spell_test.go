@@ -0,0 +1,51 @@
+package qa
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/woozymasta/dayz-stringtable/internal/hunspell"
+	"github.com/woozymasta/dayz-stringtable/internal/poutil"
+)
+
+func TestWords(t *testing.T) {
+	got := Words(`<color rgba="1,0,0,1">Load</color> %1 rounds into the M4-A1's magazine\ndon’t {name}`)
+	want := []string{"Load", "rounds", "into", "the", "M4-A1's", "magazine", "don't"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Words = %q, want %q", got, want)
+	}
+}
+
+func TestSpellCheck(t *testing.T) {
+	en, err := hunspell.Parse([]byte("SFX S Y 1\nSFX S 0 s .\n"), []byte("4\nround/S\nload\nthe\nrifle/S\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	s := &Speller{
+		Dictionaries: map[string]*hunspell.Dictionary{"english": en, OriginalLanguage: en},
+		Ignore:       map[string]bool{"mosin": true},
+	}
+	check := SpellCheck(s)
+
+	entry := &poutil.Entry{MsgID: "Load the Mosin", MsgStr: "Lood the Mosin rifles, lood %1 rounds"}
+	if got, want := check.Run("english", entry), []string{`unknown word "Lood"`, `unknown word "lood"`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Run = %q, want %q", got, want)
+	}
+	if got := check.Run("russian", entry); got != nil {
+		t.Errorf("language without dictionary reported %q", got)
+	}
+	if got := check.RunOriginal(entry); got != nil {
+		t.Errorf("RunOriginal reported %q", got)
+	}
+
+	checker := NewChecker(check)
+	po := poutil.NewFile()
+	po.Entries = []*poutil.Entry{
+		{Context: "STR_A", MsgID: "Lod the rifle"},
+		{Context: "STR_B", MsgID: "Lod", Comments: []string{"#, no-check-spelling"}},
+	}
+	findings := checker.CheckOriginals(po)
+	if len(findings) != 1 || findings[0].Key != "STR_A" || findings[0].Language != OriginalLanguage {
+		t.Errorf("unexpected original findings %+v", findings)
+	}
+}
@@ -0,0 +1,165 @@
+// Package sarif writes findings as a SARIF 2.1.0 log, the format of
+// GitHub code scanning and other static analysis dashboards.
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Version and Schema identify the SARIF format written.
+const (
+	Version = "2.1.0"
+	Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// Levels of rules and results.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+	LevelNone    = "none"
+)
+
+// Log is the root object of a SARIF file.
+type Log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema"`
+	Runs    []*Run `json:"runs"`
+}
+
+// Run holds results of a single tool invocation.
+type Run struct {
+	Tool    Tool      `json:"tool"`
+	Results []*Result `json:"results"`
+}
+
+// Tool describes the analysis tool.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver is the tool component that produced the results.
+type Driver struct {
+	Name           string  `json:"name"`
+	Version        string  `json:"version,omitempty"`
+	InformationURI string  `json:"informationUri,omitempty"`
+	Rules          []*Rule `json:"rules"`
+}
+
+// Rule is a check that produces results.
+type Rule struct {
+	ID                   string               `json:"id"`
+	ShortDescription     Message              `json:"shortDescription"`
+	DefaultConfiguration DefaultConfiguration `json:"defaultConfiguration"`
+}
+
+// DefaultConfiguration holds the default level of a rule.
+type DefaultConfiguration struct {
+	Level string `json:"level"`
+}
+
+// Message is a plain text message.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Result is a single finding.
+type Result struct {
+	RuleID    string     `json:"ruleId"`
+	Level     string     `json:"level"`
+	Message   Message    `json:"message"`
+	Locations []Location `json:"locations,omitempty"`
+}
+
+// Location points at a place in a file.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation is a file and a region in it.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+// ArtifactLocation is the URI of a file, relative to the repository root.
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is a line range, lines are 1-based.
+type Region struct {
+	StartLine int `json:"startLine"`
+}
+
+// New returns a log with a single run of the named tool.
+func New(name, version, informationURI string) *Log {
+	return &Log{
+		Version: Version,
+		Schema:  Schema,
+		Runs: []*Run{{
+			Tool: Tool{Driver: Driver{
+				Name:           name,
+				Version:        version,
+				InformationURI: informationURI,
+				Rules:          []*Rule{},
+			}},
+			Results: []*Result{},
+		}},
+	}
+}
+
+// AddRule adds a rule with its description and default level.
+func (l *Log) AddRule(id, description, level string) {
+	driver := &l.Runs[0].Tool.Driver
+	driver.Rules = append(driver.Rules, &Rule{
+		ID:                   id,
+		ShortDescription:     Message{Text: description},
+		DefaultConfiguration: DefaultConfiguration{Level: level},
+	})
+}
+
+// AddResult adds a result of a rule located at line of path. The location
+// is omitted for an empty path and the region for line 0.
+func (l *Log) AddResult(ruleID, level, message, path string, line int) {
+	result := &Result{RuleID: ruleID, Level: level, Message: Message{Text: message}}
+	if path != "" {
+		loc := PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: URI(path)}}
+		if line > 0 {
+			loc.Region = &Region{StartLine: line}
+		}
+		result.Locations = []Location{{PhysicalLocation: loc}}
+	}
+	l.Runs[0].Results = append(l.Runs[0].Results, result)
+}
+
+// Write writes the log as indented JSON.
+func (l *Log) Write(w io.Writer) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SARIF: %w", err)
+	}
+	if _, err := fmt.Fprintln(w, string(data)); err != nil {
+		return fmt.Errorf("failed to write SARIF: %w", err)
+	}
+	return nil
+}
+
+// URI converts a file path to a relative URI with forward slashes.
+// Absolute paths inside the working directory are made relative to it,
+// as code scanning resolves URIs against the repository root.
+func URI(path string) string {
+	if filepath.IsAbs(path) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(path)
+}
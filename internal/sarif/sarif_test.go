@@ -0,0 +1,51 @@
+package sarif
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLog(t *testing.T) {
+	log := New("tool", "1.0.0", "https://example.com")
+	log.AddRule("placeholders", "Placeholders match", LevelError)
+	log.AddResult("placeholders", LevelError, "missing placeholder %1", filepath.Join("l18n", "russian.po"), 12)
+	log.AddResult("placeholders", LevelError, "no location", "", 0)
+
+	var b strings.Builder
+	if err := log.Write(&b); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(b.String()), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if decoded["version"] != Version || decoded["$schema"] != Schema {
+		t.Errorf("unexpected header %v", decoded)
+	}
+
+	results := log.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	loc := results[0].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "l18n/russian.po" || loc.Region.StartLine != 12 {
+		t.Errorf("unexpected location %+v", loc)
+	}
+	if results[1].Locations != nil {
+		t.Errorf("result without path has locations %+v", results[1].Locations)
+	}
+}
+
+func TestURI(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd failed: %v", err)
+	}
+	if got := URI(filepath.Join(wd, "l18n", "german.po")); got != "l18n/german.po" {
+		t.Errorf("URI = %q, want l18n/german.po", got)
+	}
+}
@@ -0,0 +1,224 @@
+// Package srcscan finds and rewrites stringtable key references
+// in DayZ mod sources (Enforce scripts, configs, layouts and data files).
+package srcscan
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SourceExtensions lists file extensions that may contain key references.
+var SourceExtensions = []string{".c", ".cpp", ".hpp", ".layout", ".xml", ".json"}
+
+// skipDirs lists directory names that are never walked.
+var skipDirs = map[string]bool{
+	".git":         true,
+	".svn":         true,
+	"node_modules": true,
+}
+
+// IsSourceFile reports whether the path has one of the SourceExtensions.
+func IsSourceFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, item := range SourceExtensions {
+		if ext == item {
+			return true
+		}
+	}
+	return false
+}
+
+// WalkSources calls fn for every source file under dir in lexical order.
+// Hidden and VCS directories are skipped.
+func WalkSources(dir string, fn func(path string) error) error {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && (skipDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if IsSourceFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk %s: %w", dir, err)
+	}
+
+	sort.Strings(files)
+	for _, path := range files {
+		if err := fn(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplaceKeyRefs replaces "#oldKey" and "$oldKey" references in content
+// with the same prefix followed by newKey.
+// Returns the rewritten content and the number of replaced references.
+func ReplaceKeyRefs(content, oldKey, newKey string) (string, int) {
+	if oldKey == "" {
+		return content, 0
+	}
+
+	var b strings.Builder
+	count := 0
+	last := 0
+	for _, pos := range findKeyRefs(content, oldKey) {
+		b.WriteString(content[last : pos+1])
+		b.WriteString(newKey)
+		last = pos + 1 + len(oldKey)
+		count++
+	}
+	if count == 0 {
+		return content, 0
+	}
+	b.WriteString(content[last:])
+
+	return b.String(), count
+}
+
+// FindKeyRefLines returns 1-based line numbers of "#key" and "$key"
+// references in content. A line is listed once per reference.
+func FindKeyRefLines(content, key string) []int {
+	if key == "" {
+		return nil
+	}
+
+	var lines []int
+	line := 1
+	last := 0
+	for _, pos := range findKeyRefs(content, key) {
+		line += strings.Count(content[last:pos], "\n")
+		last = pos
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// findKeyRefs returns byte offsets of the '#' or '$' prefix for every
+// reference to key that is not followed by another identifier character.
+func findKeyRefs(content, key string) []int {
+	var out []int
+	for offset := 0; offset < len(content); {
+		idx := strings.Index(content[offset:], key)
+		if idx < 0 {
+			break
+		}
+		pos := offset + idx
+		end := pos + len(key)
+		offset = end
+
+		if pos == 0 || (content[pos-1] != '#' && content[pos-1] != '$') {
+			continue
+		}
+		if end < len(content) && IsIdentByte(content[end]) {
+			continue
+		}
+		out = append(out, pos-1)
+	}
+	return out
+}
+
+// IsIdentByte reports whether c may be part of a stringtable key.
+func IsIdentByte(c byte) bool {
+	return c == '_' ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
+
+// DefaultPrefixes lists key prefixes recognized without being known in advance.
+var DefaultPrefixes = []string{"STR_"}
+
+var (
+	// refPattern matches "#KEY" and "$KEY" references.
+	refPattern = regexp.MustCompile(`[#$]([A-Za-z0-9_]+)`)
+
+	// callPattern matches keys passed without prefix to localization calls.
+	callPattern = regexp.MustCompile(`\b(?:Localize|LocalizeString|TranslateString)\s*\(\s*"([A-Za-z0-9_]+)"`)
+)
+
+// Reference is a single key usage found in a source file.
+type Reference struct {
+	Key  string `json:"key"`  // Referenced key without "#" or "$" prefix
+	Path string `json:"path"` // Slash-separated path relative to the scanned directory
+	Line int    `json:"line"` // 1-based line number
+}
+
+// Scanner finds key references in mod sources.
+// A candidate is reported when it is one of Keys or starts with one of
+// Prefixes (case-insensitive); everything else, like "#include" or
+// "#FF0000", is ignored.
+type Scanner struct {
+	Keys     map[string]bool
+	Prefixes []string
+}
+
+// ScanDir walks dir and returns all references in path and line order.
+func (s *Scanner) ScanDir(dir string) ([]Reference, error) {
+	var refs []Reference
+	err := WalkSources(dir, func(path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		refs = append(refs, s.ScanContent(filepath.ToSlash(rel), string(data))...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// ScanContent returns references found in content, attributed to path.
+func (s *Scanner) ScanContent(path, content string) []Reference {
+	var refs []Reference
+	for i, line := range strings.Split(content, "\n") {
+		for _, pattern := range []*regexp.Regexp{refPattern, callPattern} {
+			for _, m := range pattern.FindAllStringSubmatchIndex(line, -1) {
+				end := m[3]
+				if end < len(line) && IsIdentByte(line[end]) {
+					continue
+				}
+				key := line[m[2]:m[3]]
+				if !s.isKey(key) {
+					continue
+				}
+				refs = append(refs, Reference{Key: key, Path: path, Line: i + 1})
+			}
+		}
+	}
+	return refs
+}
+
+// isKey reports whether a candidate looks like a stringtable key.
+func (s *Scanner) isKey(candidate string) bool {
+	if s.Keys[candidate] {
+		return true
+	}
+	upper := strings.ToUpper(candidate)
+	for _, prefix := range s.Prefixes {
+		if prefix != "" && strings.HasPrefix(upper, strings.ToUpper(prefix)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package srcscan
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReplaceKeyRefs(t *testing.T) {
+	content := `SetText("#STR_A"); name = "$STR_A"; other = "#STR_AB"; plain = "STR_A";`
+
+	got, count := ReplaceKeyRefs(content, "STR_A", "STR_B")
+	want := `SetText("#STR_B"); name = "$STR_B"; other = "#STR_AB"; plain = "STR_A";`
+	if got != want {
+		t.Errorf("ReplaceKeyRefs() = %q, want %q", got, want)
+	}
+	if count != 2 {
+		t.Errorf("ReplaceKeyRefs() count = %d, want 2", count)
+	}
+}
+
+func TestFindKeyRefLines(t *testing.T) {
+	content := "a\n\"#KEY\"\nb\n\"$KEY\" \"#KEY\"\n"
+
+	got := FindKeyRefLines(content, "KEY")
+	want := []int{2, 4, 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindKeyRefLines() = %v, want %v", got, want)
+	}
+}
+
+func TestScanner_ScanContent(t *testing.T) {
+	content := `#include "x.c"
+w.SetText("#STR_Title"); color = "#FF0000";
+string s = Widget.TranslateString("#MY_Custom");
+Localize("STR_Plain");
+displayName = "$STR_Item";`
+
+	s := &Scanner{Keys: map[string]bool{"MY_Custom": true}, Prefixes: DefaultPrefixes}
+	got := s.ScanContent("gui/menu.c", content)
+	want := []Reference{
+		{Key: "STR_Title", Path: "gui/menu.c", Line: 2},
+		{Key: "MY_Custom", Path: "gui/menu.c", Line: 3},
+		{Key: "STR_Plain", Path: "gui/menu.c", Line: 4},
+		{Key: "STR_Item", Path: "gui/menu.c", Line: 5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ScanContent() = %+v, want %+v", got, want)
+	}
+}
+
+func TestScanner_ScanDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "scripts", ".hidden"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	files := map[string]string{
+		"config.cpp":                    `displayName = "$STR_Item";`,
+		"scripts/menu.c":                `SetText("#STR_Title");`,
+		"scripts/readme.txt":            `#STR_Ignored`,
+		"scripts/.hidden/skip.c":        `#STR_Hidden`,
+		"scripts/layouts/menu.layout":   `text "#STR_Layout"`,
+		"scripts/layouts/ignored.layot": `text "#STR_Typo"`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	s := &Scanner{Prefixes: DefaultPrefixes}
+	got, err := s.ScanDir(dir)
+	if err != nil {
+		t.Fatalf("ScanDir() error: %v", err)
+	}
+	want := []Reference{
+		{Key: "STR_Item", Path: "config.cpp", Line: 1},
+		{Key: "STR_Layout", Path: "scripts/layouts/menu.layout", Line: 1},
+		{Key: "STR_Title", Path: "scripts/menu.c", Line: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ScanDir() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,210 @@
+package translate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// cacheVersion is the version of the cache file format.
+const cacheVersion = 1
+
+// CacheScoper is implemented by clients whose translations can be cached.
+// The scope names the provider and every option that changes its output
+// (model, formality, format), so changing an option misses the cache.
+type CacheScoper interface {
+	CacheScope() string
+}
+
+// Cache stores machine translations on disk, so texts are not sent to a
+// provider again in later runs or under other keys.
+type Cache struct {
+	entries map[string]*CacheEntry
+	path    string
+	dirty   bool
+}
+
+// CacheEntry is a cached translation.
+type CacheEntry struct {
+	Used   time.Time `json:"used"`
+	Text   string    `json:"text"`
+	Scope  string    `json:"scope"`
+	Source string    `json:"source,omitempty"`
+	Target string    `json:"target"`
+}
+
+// cacheFile is the on-disk layout of a cache.
+type cacheFile struct {
+	Entries map[string]*CacheEntry `json:"entries"`
+	Version int                    `json:"version"`
+}
+
+// CacheStats summarizes cache entries of a scope and target language.
+type CacheStats struct {
+	Oldest  time.Time
+	Newest  time.Time
+	Scope   string
+	Target  string
+	Entries int
+}
+
+// DefaultCachePath returns the cache file in the user cache directory.
+func DefaultCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "dayz-stringtable", "translations.json"), nil
+}
+
+// CacheKey returns the cache key of a text translated within scope from
+// source to target language with the given glossary terms.
+func CacheKey(scope, source, target, text string, glossary []GlossaryEntry) string {
+	h := sha256.New()
+	for _, part := range []string{scope, source, target, glossaryText(glossary), text} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// LoadCache reads a cache file, a missing file is an empty cache.
+func LoadCache(path string) (*Cache, error) {
+	c := &Cache{path: path, entries: make(map[string]*CacheEntry)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read translation cache: %w", err)
+	}
+
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse translation cache %s: %w", path, err)
+	}
+	if file.Version != cacheVersion {
+		return nil, fmt.Errorf("translation cache %s has unsupported version %d", path, file.Version)
+	}
+	for key, entry := range file.Entries {
+		if entry != nil {
+			c.entries[key] = entry
+		}
+	}
+	return c, nil
+}
+
+// Path returns the cache file path.
+func (c *Cache) Path() string {
+	return c.path
+}
+
+// Len returns the number of cached translations.
+func (c *Cache) Len() int {
+	return len(c.entries)
+}
+
+// Get returns the cached translation of key and marks it used.
+func (c *Cache) Get(key string) (string, bool) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	entry.Used = time.Now().UTC()
+	c.dirty = true
+	return entry.Text, true
+}
+
+// Put stores a translation under key.
+func (c *Cache) Put(key string, entry CacheEntry) {
+	entry.Used = time.Now().UTC()
+	c.entries[key] = &entry
+	c.dirty = true
+}
+
+// Prune removes entries not used since before and returns their number.
+// A zero time removes all entries.
+func (c *Cache) Prune(before time.Time) int {
+	removed := 0
+	for key, entry := range c.entries {
+		if before.IsZero() || entry.Used.Before(before) {
+			delete(c.entries, key)
+			removed++
+		}
+	}
+	if removed > 0 {
+		c.dirty = true
+	}
+	return removed
+}
+
+// Stats returns entry counts per scope and target language, sorted.
+func (c *Cache) Stats() []CacheStats {
+	groups := make(map[[2]string]*CacheStats)
+	for _, entry := range c.entries {
+		id := [2]string{entry.Scope, entry.Target}
+		s, ok := groups[id]
+		if !ok {
+			s = &CacheStats{Scope: entry.Scope, Target: entry.Target, Oldest: entry.Used, Newest: entry.Used}
+			groups[id] = s
+		}
+		s.Entries++
+		if entry.Used.Before(s.Oldest) {
+			s.Oldest = entry.Used
+		}
+		if entry.Used.After(s.Newest) {
+			s.Newest = entry.Used
+		}
+	}
+
+	out := make([]CacheStats, 0, len(groups))
+	for _, s := range groups {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Scope != out[j].Scope {
+			return out[i].Scope < out[j].Scope
+		}
+		return out[i].Target < out[j].Target
+	})
+	return out
+}
+
+// Save writes the cache if it changed. The file is replaced atomically,
+// so an interrupted run never leaves a truncated cache.
+func (c *Cache) Save() error {
+	if !c.dirty {
+		return nil
+	}
+	data, err := json.Marshal(cacheFile{Version: cacheVersion, Entries: c.entries})
+	if err != nil {
+		return fmt.Errorf("failed to marshal translation cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write translation cache: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write translation cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write translation cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to write translation cache: %w", err)
+	}
+	c.dirty = false
+	return nil
+}
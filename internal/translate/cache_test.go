@@ -0,0 +1,58 @@
+package translate
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "translations.json")
+	cache, err := LoadCache(path)
+	if err != nil {
+		t.Fatalf("LoadCache of a missing file failed: %v", err)
+	}
+	if cache.Len() != 0 {
+		t.Fatalf("new cache has %d entries", cache.Len())
+	}
+
+	key := CacheKey("deepl formality=more", "EN", "RU", "Close", nil)
+	cache.Put(key, CacheEntry{Text: "Закрыть", Scope: "deepl formality=more", Source: "EN", Target: "RU"})
+	cache.Put(CacheKey("deepl formality=more", "EN", "DE", "Close", nil), CacheEntry{Text: "Schließen", Scope: "deepl formality=more", Target: "DE"})
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadCache(path)
+	if err != nil {
+		t.Fatalf("LoadCache failed: %v", err)
+	}
+	if text, ok := loaded.Get(key); !ok || text != "Закрыть" {
+		t.Errorf("Get = %q, %v", text, ok)
+	}
+	if stats := loaded.Stats(); len(stats) != 2 || stats[0].Target != "DE" || stats[1].Entries != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	if n := loaded.Prune(time.Now().Add(-time.Hour)); n != 0 {
+		t.Errorf("pruned %d recently used entries", n)
+	}
+	if n := loaded.Prune(time.Time{}); n != 2 || loaded.Len() != 0 {
+		t.Errorf("Prune all removed %d, left %d", n, loaded.Len())
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	base := CacheKey("openai model=a", "English", "Russian", "Close", nil)
+	for name, key := range map[string]string{
+		"scope":    CacheKey("openai model=b", "English", "Russian", "Close", nil),
+		"source":   CacheKey("openai model=a", "", "Russian", "Close", nil),
+		"target":   CacheKey("openai model=a", "English", "German", "Close", nil),
+		"text":     CacheKey("openai model=a", "English", "Russian", "Close ", nil),
+		"glossary": CacheKey("openai model=a", "English", "Russian", "Close", []GlossaryEntry{{Source: "Close", Target: "Закрыть"}}),
+	} {
+		if key == base {
+			t.Errorf("key doesn't depend on %s", name)
+		}
+	}
+}
@@ -5,9 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"strings"
 	"time"
 )
 
@@ -22,6 +20,7 @@ type deeplRequest struct {
 	SourceLang         string   `json:"source_lang,omitempty"`
 	Formality          string   `json:"formality,omitempty"`
 	SplitSentences     string   `json:"split_sentences,omitempty"`
+	Context            string   `json:"context,omitempty"`
 	Texts              []string `json:"text"`
 	PreserveFormatting int      `json:"preserve_formatting,omitempty"`
 }
@@ -29,6 +28,7 @@ type deeplRequest struct {
 // DeeplClient implements the DeepL API.
 type DeeplClient struct {
 	HTTPClient         *http.Client
+	Retry              *RetryPolicy // Retries and rate limit, DefaultRetryPolicy if nil
 	URL                string
 	AuthKey            string
 	SourceLang         string
@@ -46,6 +46,11 @@ func (c *DeeplClient) httpClient() *http.Client {
 	return &http.Client{Timeout: 60 * time.Second}
 }
 
+// CacheScope identifies DeepL translations made with the client options.
+func (c *DeeplClient) CacheScope() string {
+	return fmt.Sprintf("deepl source=%s formality=%s split=%s preserve=%t", c.SourceLang, c.Formality, c.SplitSentences, c.PreserveFormatting)
+}
+
 // Translate sends a request to DeepL and returns translated strings in order.
 func (c *DeeplClient) Translate(ctx context.Context, req Request) ([]string, error) {
 	if c.AuthKey == "" {
@@ -80,32 +85,27 @@ func (c *DeeplClient) Translate(ctx context.Context, req Request) ([]string, err
 		PreserveFormatting: boolToInt(c.PreserveFormatting),
 		SplitSentences:     c.SplitSentences,
 	}
+	if terms := glossaryText(req.Glossary); terms != "" {
+		// Context is not translated, it only influences the translation
+		payload.Context = "Terminology: " + terms
+	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("deepl marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("deepl request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "DeepL-Auth-Key "+c.AuthKey)
-
-	resp, err := c.httpClient().Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("deepl request failed: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, err := send(ctx, c.Retry, "deepl", c.httpClient(), func(ctx context.Context) (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", "DeepL-Auth-Key "+c.AuthKey)
+		return httpReq, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("deepl read response: %w", err)
-	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("deepl response %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		return nil, err
 	}
 
 	var parsed struct {
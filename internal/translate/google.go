@@ -5,10 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
-	"strings"
 	"time"
 )
 
@@ -24,8 +22,10 @@ type googleRequest struct {
 }
 
 // GoogleClient implements the Google Translate v2 API.
+// Request glossary entries are ignored, the v2 API has no glossary support.
 type GoogleClient struct {
 	HTTPClient *http.Client
+	Retry      *RetryPolicy // Retries and rate limit, DefaultRetryPolicy if nil
 	URL        string
 	APIKey     string
 	SourceLang string
@@ -40,6 +40,11 @@ func (c *GoogleClient) httpClient() *http.Client {
 	return &http.Client{Timeout: 60 * time.Second}
 }
 
+// CacheScope identifies Google translations made with the client options.
+func (c *GoogleClient) CacheScope() string {
+	return fmt.Sprintf("google source=%s format=%s", c.SourceLang, c.Format)
+}
+
 // Translate sends a request to Google Translate and returns translated strings in order.
 func (c *GoogleClient) Translate(ctx context.Context, req Request) ([]string, error) {
 	if c.APIKey == "" {
@@ -82,25 +87,16 @@ func (c *GoogleClient) Translate(ctx context.Context, req Request) ([]string, er
 	query.Set("key", c.APIKey)
 	endpointURL.RawQuery = query.Encode()
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpointURL.String(), bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("google request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient().Do(httpReq)
+	respBody, err := send(ctx, c.Retry, "google", c.httpClient(), func(ctx context.Context) (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpointURL.String(), bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		return httpReq, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("google request failed: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("google read response: %w", err)
-	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("google response %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		return nil, err
 	}
 
 	var parsed struct {
@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -34,11 +33,13 @@ type openAIResponse struct {
 const (
 	openAIPromptPreserve = "Preserve punctuation, spacing, and placeholders like {name}, %%s, {0}, or <tag>."
 	openAIPromptJSONOnly = "Return ONLY a JSON array of strings in the same order."
+	openAIPromptGlossary = "Always translate these terms exactly as given (a term equal to its translation must stay untranslated): %s."
 )
 
 // OpenAIClient implements OpenAI-compatible chat completions.
 type OpenAIClient struct {
 	HTTPClient  *http.Client
+	Retry       *RetryPolicy // Retries and rate limit, DefaultRetryPolicy if nil
 	BaseURL     string
 	APIKey      string
 	Model       string
@@ -53,6 +54,12 @@ func (c *OpenAIClient) httpClient() *http.Client {
 	return &http.Client{Timeout: 60 * time.Second}
 }
 
+// CacheScope identifies translations of the model at the endpoint with
+// the client temperature.
+func (c *OpenAIClient) CacheScope() string {
+	return fmt.Sprintf("openai url=%s model=%s temperature=%g", c.BaseURL, c.Model, c.Temperature)
+}
+
 // Translate sends a chat completion request and returns translated strings in order.
 func (c *OpenAIClient) Translate(ctx context.Context, req Request) ([]string, error) {
 	if c.APIKey == "" {
@@ -76,6 +83,9 @@ func (c *OpenAIClient) Translate(ctx context.Context, req Request) ([]string, er
 	}
 
 	system := buildSystemPrompt(req.SourceLang, req.TargetLang)
+	if terms := glossaryText(req.Glossary); terms != "" {
+		system += " " + fmt.Sprintf(openAIPromptGlossary, terms)
+	}
 	input, err := json.Marshal(req.Texts)
 	if err != nil {
 		return nil, fmt.Errorf("openai marshal input: %w", err)
@@ -98,26 +108,17 @@ func (c *OpenAIClient) Translate(ctx context.Context, req Request) ([]string, er
 	}
 
 	endpoint := baseURL + "/chat/completions"
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	respBody, err := send(ctx, c.Retry, "openai", c.httpClient(), func(ctx context.Context) (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", "Bearer "+c.APIKey)
+		return httpReq, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("openai request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.APIKey)
-
-	resp, err := c.httpClient().Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("openai request failed: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("openai read response: %w", err)
-	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("openai response %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		return nil, err
 	}
 
 	var parsed openAIResponse
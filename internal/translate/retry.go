@@ -0,0 +1,289 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrorKind classifies provider errors by whether a retry can succeed.
+type ErrorKind int
+
+// Error kinds, only transient errors are retried.
+const (
+	ErrorTransient ErrorKind = iota // Network errors, rate limits and server errors
+	ErrorAuth                       // Missing or invalid credentials
+	ErrorQuota                      // Exhausted character or billing quota
+	ErrorPermanent                  // Other rejected requests
+)
+
+// String returns the name of the error kind.
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorTransient:
+		return "transient"
+	case ErrorAuth:
+		return "auth"
+	case ErrorQuota:
+		return "quota"
+	}
+	return "permanent"
+}
+
+// APIError is an unsuccessful response of a provider.
+type APIError struct {
+	Provider   string
+	Body       string
+	RetryAfter time.Duration // Delay requested by the Retry-After header
+	StatusCode int
+	Kind       ErrorKind
+}
+
+// Error returns the provider, status code and response body.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s response %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// ErrorKindOf returns the kind of an error returned by a client. Of errors
+// that are not provider responses only network errors and timeouts are
+// transient, others like TLS or invalid URL errors are permanent.
+func ErrorKindOf(err error) ErrorKind {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Kind
+	}
+
+	// Every error of http.Client.Do is an url.Error, which is a net.Error
+	// itself, so classify the error it wraps
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return ErrorTransient
+	}
+	return ErrorPermanent
+}
+
+// classifyResponse returns the kind of an error response. Providers report
+// quota exhaustion differently: DeepL with status 456, OpenAI with 429 and
+// an insufficient_quota code, Google with 403 and a limit reason.
+func classifyResponse(status int, body string) ErrorKind {
+	lower := strings.ToLower(body)
+	switch {
+	case status == 456 || strings.Contains(lower, "insufficient_quota") ||
+		strings.Contains(lower, "dailylimitexceeded") || strings.Contains(lower, "quotaexceeded"):
+		return ErrorQuota
+	case status == http.StatusForbidden && strings.Contains(lower, "ratelimitexceeded"):
+		return ErrorTransient
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrorAuth
+	case status == http.StatusTooManyRequests || status == http.StatusRequestTimeout || status >= 500:
+		return ErrorTransient
+	}
+	return ErrorPermanent
+}
+
+// parseRetryAfter parses a Retry-After header in seconds or as an HTTP
+// date. Returns 0 for a missing or invalid header.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// RetryPolicy retries transient errors with exponential backoff and jitter
+// and limits the request rate. A nil policy uses DefaultRetryPolicy.
+type RetryPolicy struct {
+	Limiter     *RateLimiter                                      // Optional request rate limit
+	Notify      func(err error, delay time.Duration, attempt int) // Optional callback before a retry
+	BaseDelay   time.Duration                                     // Delay before the first retry, doubled for every next one
+	MaxDelay    time.Duration                                     // Upper bound of backoff delays, a longer Retry-After fails the request
+	MaxAttempts int                                               // Attempts including the first, 1 disables retries
+}
+
+// DefaultRetryPolicy returns the policy of clients without one: four
+// attempts starting with a one second delay, without a rate limit.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: time.Minute}
+}
+
+// backoff returns the delay before retry number attempt (1-based): the
+// base delay doubled per attempt and capped, minus up to half as jitter.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 1 {
+		return delay
+	}
+	// #nosec G404 -- jitter needs no cryptographic randomness
+	return delay - rand.N(delay/2)
+}
+
+// send performs a request built by newRequest, retrying transient errors,
+// and returns the body of a successful response. newRequest is called for
+// every attempt, as a request body can be read only once.
+func send(ctx context.Context, policy *RetryPolicy, provider string, client *http.Client, newRequest func(context.Context) (*http.Request, error)) ([]byte, error) {
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+	attempts := max(policy.MaxAttempts, 1)
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		if policy.Limiter != nil {
+			if err := policy.Limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := newRequest(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s request: %w", provider, err)
+		}
+		body, err := sendOnce(provider, client, req)
+		if err == nil {
+			return body, nil
+		}
+		if ctx.Err() != nil || ErrorKindOf(err) != ErrorTransient {
+			return nil, err
+		}
+		lastErr = err
+		if attempt >= attempts {
+			break
+		}
+
+		delay := policy.backoff(attempt)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			// Never retry before the server allows it
+			if policy.MaxDelay > 0 && apiErr.RetryAfter > policy.MaxDelay {
+				return nil, fmt.Errorf("%w (Retry-After %s exceeds the maximum delay %s)", err, apiErr.RetryAfter, policy.MaxDelay)
+			}
+			delay = apiErr.RetryAfter
+		}
+		if policy.Notify != nil {
+			policy.Notify(err, delay, attempt)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+	if attempts > 1 {
+		return nil, fmt.Errorf("%w (gave up after %d attempts)", lastErr, attempts)
+	}
+	return nil, lastErr
+}
+
+// sendOnce performs a single request and returns the response body, or
+// an APIError for unsuccessful responses.
+func sendOnce(provider string, client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", provider, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s read response: %w", provider, err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return body, nil
+	}
+
+	text := strings.TrimSpace(string(body))
+	return nil, &APIError{
+		Provider:   provider,
+		StatusCode: resp.StatusCode,
+		Body:       text,
+		Kind:       classifyResponse(resp.StatusCode, text),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RateLimiter is a token bucket limiting the request rate. It holds up to
+// burst tokens, refilled at rate tokens per second, and every request
+// takes one.
+type RateLimiter struct {
+	last   time.Time
+	rate   float64
+	burst  float64
+	tokens float64
+	mu     sync.Mutex
+}
+
+// NewRateLimiter returns a limiter of perMinute requests with bursts of
+// up to burst requests. Returns nil, no limit, if perMinute is not positive.
+func NewRateLimiter(perMinute float64, burst int) *RateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	b := float64(max(burst, 1))
+	return &RateLimiter{rate: perMinute / 60, burst: b, tokens: b}
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		if !l.last.IsZero() {
+			l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		}
+		l.last = now
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
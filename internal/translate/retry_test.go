@@ -0,0 +1,287 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testPolicy retries quickly so tests don't wait for real backoff delays.
+func testPolicy(attempts int) *RetryPolicy {
+	return &RetryPolicy{MaxAttempts: attempts, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+}
+
+// flakyServer answers with the given error responses in turn, then with a
+// successful DeepL response. It returns the server and its request counter.
+func flakyServer(t *testing.T, failures ...func(w http.ResponseWriter)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := int(calls.Add(1))
+		if n <= len(failures) {
+			failures[n-1](w)
+			return
+		}
+		_, _ = w.Write([]byte(`{"translations":[{"text":"Привет"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+// status returns a failure writing a status code, body and headers.
+func status(code int, body string, headers ...string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestSendRetriesTransientErrors(t *testing.T) {
+	srv, calls := flakyServer(t,
+		status(http.StatusTooManyRequests, "slow down", "Retry-After", "0"),
+		status(http.StatusServiceUnavailable, "busy"),
+	)
+	var retries int
+	policy := testPolicy(3)
+	policy.Notify = func(_ error, _ time.Duration, _ int) { retries++ }
+
+	client := &DeeplClient{URL: srv.URL, AuthKey: "key", Retry: policy}
+	out, err := client.Translate(context.Background(), Request{TargetLang: "RU", Texts: []string{"Hello"}})
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if len(out) != 1 || out[0] != "Привет" {
+		t.Errorf("unexpected translation %v", out)
+	}
+	if calls.Load() != 3 || retries != 2 {
+		t.Errorf("got %d calls and %d retries, want 3 and 2", calls.Load(), retries)
+	}
+}
+
+func TestSendGivesUp(t *testing.T) {
+	busy := status(http.StatusBadGateway, "bad gateway")
+	srv, calls := flakyServer(t, busy, busy, busy, busy)
+
+	client := &DeeplClient{URL: srv.URL, AuthKey: "key", Retry: testPolicy(3)}
+	_, err := client.Translate(context.Background(), Request{TargetLang: "RU", Texts: []string{"Hello"}})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected API error 502, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("got %d calls, want 3", calls.Load())
+	}
+}
+
+func TestSendFailsFast(t *testing.T) {
+	tests := []struct {
+		failure func(w http.ResponseWriter)
+		name    string
+		kind    ErrorKind
+	}{
+		{status(http.StatusForbidden, "Wrong auth key"), "deepl auth", ErrorAuth},
+		{status(456, "Quota exceeded"), "deepl quota", ErrorQuota},
+		{status(http.StatusTooManyRequests, `{"error":{"code":"insufficient_quota"}}`), "openai quota", ErrorQuota},
+		{status(http.StatusBadRequest, "bad target_lang"), "bad request", ErrorPermanent},
+	}
+	for _, tt := range tests {
+		srv, calls := flakyServer(t, tt.failure)
+		client := &DeeplClient{URL: srv.URL, AuthKey: "key", Retry: testPolicy(4)}
+		_, err := client.Translate(context.Background(), Request{TargetLang: "RU", Texts: []string{"Hello"}})
+		if kind := ErrorKindOf(err); err == nil || kind != tt.kind {
+			t.Errorf("%s: got %v (%s), want %s", tt.name, err, kind, tt.kind)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("%s: got %d calls, want 1", tt.name, calls.Load())
+		}
+	}
+}
+
+func TestSendContextCanceled(t *testing.T) {
+	srv, _ := flakyServer(t, status(http.StatusServiceUnavailable, "busy", "Retry-After", "60"))
+	policy := testPolicy(3)
+	policy.MaxDelay = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	client := &DeeplClient{URL: srv.URL, AuthKey: "key", Retry: policy}
+	start := time.Now()
+	_, err := client.Translate(ctx, Request{TargetLang: "RU", Texts: []string{"Hello"}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Retry-After wait was not interrupted by the context")
+	}
+}
+
+func TestSendRetryAfter(t *testing.T) {
+	srv, calls := flakyServer(t, status(http.StatusTooManyRequests, "slow down", "Retry-After", "1"))
+	var delay time.Duration
+	policy := testPolicy(2)
+	policy.MaxDelay = 2 * time.Second
+	policy.Notify = func(_ error, d time.Duration, _ int) { delay = d }
+
+	client := &DeeplClient{URL: srv.URL, AuthKey: "key", Retry: policy}
+	if _, err := client.Translate(context.Background(), Request{TargetLang: "RU", Texts: []string{"Hello"}}); err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if calls.Load() != 2 || delay != time.Second {
+		t.Errorf("got %d calls after %s, want 2 after the Retry-After of 1s", calls.Load(), delay)
+	}
+
+	// A Retry-After above the maximum delay fails instead of retrying early
+	srv, calls = flakyServer(t, status(http.StatusTooManyRequests, "slow down", "Retry-After", "60"))
+	client = &DeeplClient{URL: srv.URL, AuthKey: "key", Retry: testPolicy(3)}
+	_, err := client.Translate(context.Background(), Request{TargetLang: "RU", Texts: []string{"Hello"}})
+	if err == nil || calls.Load() != 1 {
+		t.Errorf("got %v after %d calls, want an error after 1 call", err, calls.Load())
+	}
+}
+
+func TestSendClientErrors(t *testing.T) {
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	tlsSrv := httptest.NewUnstartedServer(http.NotFoundHandler())
+	tlsSrv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	tlsSrv.StartTLS()
+	t.Cleanup(tlsSrv.Close)
+
+	tests := []struct {
+		name string
+		url  string
+		kind ErrorKind
+	}{
+		{"connection refused", closed.URL, ErrorTransient},
+		{"unknown certificate", tlsSrv.URL, ErrorPermanent},
+		{"unsupported scheme", "ftp://localhost/translate", ErrorPermanent},
+	}
+	for _, tt := range tests {
+		var retries int
+		policy := testPolicy(2)
+		policy.Notify = func(_ error, _ time.Duration, _ int) { retries++ }
+
+		client := &DeeplClient{URL: tt.url, AuthKey: "key", Retry: policy}
+		_, err := client.Translate(context.Background(), Request{TargetLang: "RU", Texts: []string{"Hello"}})
+		if kind := ErrorKindOf(err); err == nil || kind != tt.kind {
+			t.Errorf("%s: got %v (%s), want %s", tt.name, err, kind, tt.kind)
+		}
+		if want := map[ErrorKind]int{ErrorTransient: 1}[tt.kind]; retries != want {
+			t.Errorf("%s: got %d retries, want %d", tt.name, retries, want)
+		}
+	}
+}
+
+func TestClientsRetry(t *testing.T) {
+	for name, newClient := range map[string]func(url string) (Client, string){
+		"openai": func(url string) (Client, string) {
+			return &OpenAIClient{BaseURL: url, APIKey: "key", Retry: testPolicy(2)},
+				`{"choices":[{"message":{"content":"[\"Привет\"]"}}]}`
+		},
+		"google": func(url string) (Client, string) {
+			return &GoogleClient{URL: url, APIKey: "key", Retry: testPolicy(2)},
+				`{"data":{"translations":[{"translatedText":"Привет"}]}}`
+		},
+	} {
+		var calls atomic.Int32
+		var success string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(success))
+		}))
+		var client Client
+		client, success = newClient(srv.URL)
+
+		out, err := client.Translate(context.Background(), Request{TargetLang: "ru", Texts: []string{"Hello"}})
+		srv.Close()
+		if err != nil || len(out) != 1 || out[0] != "Привет" {
+			t.Errorf("%s: got %v, %v after a retry", name, out, err)
+		}
+		if calls.Load() != 2 {
+			t.Errorf("%s: got %d calls, want 2", name, calls.Load())
+		}
+	}
+}
+
+func TestClassifyResponse(t *testing.T) {
+	tests := []struct {
+		body   string
+		status int
+		want   ErrorKind
+	}{
+		{"", http.StatusUnauthorized, ErrorAuth},
+		{`{"error":{"errors":[{"reason":"userRateLimitExceeded"}]}}`, http.StatusForbidden, ErrorTransient},
+		{`{"error":{"errors":[{"reason":"dailyLimitExceeded"}]}}`, http.StatusForbidden, ErrorQuota},
+		{"", http.StatusRequestTimeout, ErrorTransient},
+		{"", http.StatusInternalServerError, ErrorTransient},
+		{"", http.StatusNotFound, ErrorPermanent},
+	}
+	for _, tt := range tests {
+		if got := classifyResponse(tt.status, tt.body); got != tt.want {
+			t.Errorf("classifyResponse(%d, %q) = %s, want %s", tt.status, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"7":                             7 * time.Second,
+		"-1":                            0,
+		"soon":                          0,
+		"Mon, 01 Jan 2024 12:00:30 GMT": 30 * time.Second,
+		"Mon, 01 Jan 2024 11:00:00 GMT": 0,
+	}
+	for value, want := range tests {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, limit := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		if got := policy.backoff(attempt); got < limit/2 || got > limit {
+			t.Errorf("backoff(%d) = %s, want between %s and %s", attempt, got, limit/2, limit)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	if NewRateLimiter(0, 1) != nil {
+		t.Error("expected no limiter for rate 0")
+	}
+
+	limiter := NewRateLimiter(1200, 2) // 20 per second, bursts of 2
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	// Two requests of the burst are free, two more wait 50ms each
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("4 requests took %s, want at least 100ms", elapsed)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := limiter.Wait(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected canceled wait, got %v", err)
+	}
+}
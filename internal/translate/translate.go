@@ -13,6 +13,24 @@ type Request struct {
 	SourceLang string
 	TargetLang string
 	Texts      []string
+	Glossary   []GlossaryEntry // Terms found in Texts with their required translation
+}
+
+// GlossaryEntry is a source term with its required translation.
+// Target equals Source for terms that must not be translated.
+type GlossaryEntry struct {
+	Source string
+	Target string
+}
+
+// glossaryText renders glossary entries as "Source = Target" pairs
+// separated by semicolons, or an empty string.
+func glossaryText(entries []GlossaryEntry) string {
+	parts := make([]string, 0, len(entries))
+	for _, e := range entries {
+		parts = append(parts, fmt.Sprintf("%q = %q", e.Source, e.Target))
+	}
+	return strings.Join(parts, "; ")
 }
 
 // Client translates batches of strings.
@@ -69,14 +69,8 @@ if (-not (Test-Path $CSV_TEMPLATE)) {
 # Join languages with comma
 $langsString = $langs -join ","
 
-# Update or create PO files
-if ((Test-Path $env:PO_DIR) -and (Test-Path $POT_FILE)) {
-    # Update with new strings
-    dayz-stringtable update -i $CSV_TEMPLATE -d $env:PO_DIR -l $langsString
-} else {
-    # First run, create po files
-    dayz-stringtable pos -i $CSV_TEMPLATE -d $env:PO_DIR -f -l $langsString
-}
+# Update with new strings, PO files of new languages are created
+dayz-stringtable update -i $CSV_TEMPLATE -d $env:PO_DIR -l $langsString
 
 dayz-stringtable pot -i $CSV_TEMPLATE -o $POT_FILE -f
 dayz-stringtable make -i $CSV_TEMPLATE -d $env:PO_DIR -o $CSV_RESULT -f
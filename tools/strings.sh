@@ -37,15 +37,9 @@ if [ ! -f "$CSV_TEMPLATE" ]; then
   echo "Init base template $CSV_TEMPLATE"
 fi
 
-if [ -d "$PO_DIR" ] && [ -f "$POT_FILE" ]; then
-  # update with new strings
-  dayz-stringtable update -i "$CSV_TEMPLATE" -d "$PO_DIR" \
-    -l "$( IFS=,; echo "${langs[*]}" )" -P "$PROJECT_VERSION"
-else
-  # first run, create po files
-  dayz-stringtable pos -i "$CSV_TEMPLATE" -d "$PO_DIR" -f \
-    -l "$( IFS=,; echo "${langs[*]}" )" -P "$PROJECT_VERSION"
-fi
+# update with new strings, PO files of new languages are created
+dayz-stringtable update -i "$CSV_TEMPLATE" -d "$PO_DIR" \
+  -l "$( IFS=,; echo "${langs[*]}" )" -P "$PROJECT_VERSION"
 
 dayz-stringtable pot -i "$CSV_TEMPLATE" -o "$POT_FILE" -f -P "$PROJECT_VERSION"
 dayz-stringtable make -i "$CSV_TEMPLATE" -d "$PO_DIR" -o "$CSV_RESULT" -f